@@ -0,0 +1,193 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Scopes of the API keys.
+const (
+	ScopeUsersRead = "users:read"
+	// ScopeUsersReadPublic allows reading only the fields of
+	// PublicProjection, e.g. for partner services.
+	ScopeUsersReadPublic = "users:read_public"
+	ScopeUsersWrite      = "users:write"
+	ScopeUsersDelete     = "users:delete"
+	ScopeRolesWrite      = "roles:write"
+)
+
+// Scopes contains all valid scopes.
+var Scopes = []string{ScopeUsersRead, ScopeUsersReadPublic, ScopeUsersWrite, ScopeUsersDelete, ScopeRolesWrite}
+
+// apiKeyPrefix makes the keys easy to spot, e.g. in secret scanners.
+const apiKeyPrefix = "usk_"
+
+// lastUsedInterval avoids writing the last used timestamp on every request.
+const lastUsedInterval = time.Minute
+
+var (
+	ErrUnauthenticated  = &Err{Type: Unauthenticated, Code: "unauthenticated", Message: "Missing or invalid API key"}
+	ErrPermissionDenied = &Err{Type: PermissionDenied, Code: "permission_denied", Message: "API key doesn't have the required scope"}
+	ErrAPIKeyNotFound   = &Err{Type: NotFound, Code: "api_key_not_found", Message: "API key not found"}
+)
+
+//go:generate mockgen -package mock -mock_names APIKeyService=APIKeyService -destination mock/apikeysvc.go github.com/guilherme-santos/user APIKeyService
+
+// APIKeyService is an interface which implements the management and the
+// authentication of API keys.
+type APIKeyService interface {
+	// CreateAPIKey creates a new key returning it together with its secret,
+	// which is not stored and can't be retrieved later.
+	CreateAPIKey(_ context.Context, k *APIKey) (secret string, _ error)
+	RevokeAPIKey(_ context.Context, id string) error
+	// Authenticate returns the key of secret, ErrUnauthenticated is returned
+	// when it doesn't exist, is expired or revoked.
+	Authenticate(_ context.Context, secret string) (*APIKey, error)
+}
+
+//go:generate mockgen -package mock -mock_names APIKeyStorage=APIKeyStorage -destination mock/apikeystorage.go github.com/guilherme-santos/user APIKeyStorage
+
+// APIKeyStorage is an interface which implements the storage of API keys,
+// only the hash of the secret is stored.
+type APIKeyStorage interface {
+	Create(_ context.Context, k *APIKey, hash string) error
+	// GetByHash returns ErrAPIKeyNotFound when there's no key with hash.
+	GetByHash(_ context.Context, hash string) (*APIKey, error)
+	// Revoke returns ErrAPIKeyNotFound when there's no active key with id.
+	Revoke(_ context.Context, id string) error
+	UpdateLastUsed(_ context.Context, id string, t time.Time) error
+}
+
+// APIKey grants its scopes to whoever has its secret.
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	// TenantID restricts the key to a tenant, keys without it can access any
+	// tenant selected by the X-Tenant-ID header.
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+func (k APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (k APIKey) Validate() error {
+	if k.Name == "" {
+		return NewMissingFieldError("name")
+	}
+	if k.TenantID != "" && ValidateTenant(k.TenantID) != nil {
+		return &FieldError{Err: *ErrInvalidTenant, Field: "tenant_id"}
+	}
+	if len(k.Scopes) == 0 {
+		return NewMissingFieldError("scopes")
+	}
+	for _, s := range k.Scopes {
+		if !validScope(s) {
+			return &FieldError{
+				Err: Error{
+					Type:    InvalidArgument,
+					Code:    "invalid_scope",
+					Message: "Scope must be one of " + strings.Join(Scopes, ", "),
+				},
+				Field: "scopes",
+			}
+		}
+	}
+	return nil
+}
+
+func validScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type APIKeyServiceImpl struct {
+	storage APIKeyStorage
+}
+
+// Make sure APIKeyServiceImpl implements APIKeyService
+var _ APIKeyService = &APIKeyServiceImpl{}
+
+func NewAPIKeyService(storage APIKeyStorage) *APIKeyServiceImpl {
+	return &APIKeyServiceImpl{
+		storage: storage,
+	}
+}
+
+func (s APIKeyServiceImpl) CreateAPIKey(ctx context.Context, k *APIKey) (string, error) {
+	err := k.Validate()
+	if err != nil {
+		return "", err
+	}
+
+	b := make([]byte, 32)
+	_, err = rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	secret := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	err = s.storage.Create(ctx, k, HashSecret(secret))
+	if err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+func (s APIKeyServiceImpl) RevokeAPIKey(ctx context.Context, id string) error {
+	return s.storage.Revoke(ctx, id)
+}
+
+func (s APIKeyServiceImpl) Authenticate(ctx context.Context, secret string) (*APIKey, error) {
+	if !strings.HasPrefix(secret, apiKeyPrefix) {
+		return nil, ErrUnauthenticated
+	}
+	k, err := s.storage.GetByHash(ctx, HashSecret(secret))
+	if err == ErrAPIKeyNotFound {
+		return nil, ErrUnauthenticated
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if k.RevokedAt != nil || (k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)) {
+		return nil, ErrUnauthenticated
+	}
+	if k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= lastUsedInterval {
+		err = s.storage.UpdateLastUsed(ctx, k.ID, now)
+		if err != nil {
+			// the key is still valid, so it's only logged
+			Logger(ctx).WithError(err).Error("unable to update api key last used")
+		}
+		k.LastUsedAt = &now
+	}
+	return k, nil
+}
+
+// HashSecret returns the hash stored of random secrets, like API keys and
+// refresh tokens. As they are random there's no need for a slow hash like
+// bcrypt, which would be paid on every request.
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,108 @@
+package user_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyServiceCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	k := &user.APIKey{Name: "backoffice", Scopes: []string{user.ScopeUsersRead}}
+
+	// Only the hash of the secret is stored
+	var hash string
+	storage := mock.NewAPIKeyStorage(ctrl)
+	storage.EXPECT().
+		Create(gomock.Any(), k, gomock.Any()).
+		DoAndReturn(func(_ context.Context, k *user.APIKey, h string) error {
+			hash = h
+			return nil
+		})
+
+	svc := user.NewAPIKeyService(storage)
+	secret, err := svc.CreateAPIKey(ctx, k)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, "usk_"))
+	assert.Equal(t, user.HashSecret(secret), hash)
+	assert.NotContains(t, hash, secret)
+
+	// Scopes are validated
+	_, err = svc.CreateAPIKey(ctx, &user.APIKey{Name: "backoffice", Scopes: []string{"users:all"}})
+	if ferr, ok := err.(*user.FieldError); assert.True(t, ok) {
+		assert.Equal(t, "invalid_scope", ferr.Code)
+	}
+}
+
+func TestAPIKeyServiceAuthenticate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	recently := time.Now().Add(-time.Second)
+
+	tests := []struct {
+		name      string
+		key       *user.APIKey
+		err       error
+		touchUsed bool
+	}{
+		{
+			name:      "valid",
+			key:       &user.APIKey{ID: "key-1", ExpiresAt: &future},
+			touchUsed: true,
+		},
+		{
+			name: "recently used",
+			key:  &user.APIKey{ID: "key-1", LastUsedAt: &recently},
+		},
+		{
+			name: "not found",
+			err:  user.ErrUnauthenticated,
+		},
+		{
+			name: "expired",
+			key:  &user.APIKey{ID: "key-1", ExpiresAt: &past},
+			err:  user.ErrUnauthenticated,
+		},
+		{
+			name: "revoked",
+			key:  &user.APIKey{ID: "key-1", RevokedAt: &past},
+			err:  user.ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+			secret := "usk_secret"
+
+			storage := mock.NewAPIKeyStorage(ctrl)
+			if tt.key != nil {
+				storage.EXPECT().GetByHash(gomock.Any(), user.HashSecret(secret)).Return(tt.key, nil)
+			} else {
+				storage.EXPECT().GetByHash(gomock.Any(), user.HashSecret(secret)).Return(nil, user.ErrAPIKeyNotFound)
+			}
+			if tt.touchUsed {
+				storage.EXPECT().UpdateLastUsed(gomock.Any(), tt.key.ID, gomock.Any()).Return(nil)
+			}
+
+			k, err := user.NewAPIKeyService(storage).Authenticate(ctx, secret)
+			assert.Equal(t, tt.err, err)
+			if tt.err == nil {
+				assert.Equal(t, tt.key, k)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package user
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	AuditCreated = "user.created"
+	AuditUpdated = "user.updated"
+	AuditDeleted = "user.deleted"
+
+	// AnonymousActor is used when the actor of a change is unknown.
+	AnonymousActor = "anonymous"
+	// Redacted replaces sensitive values in the audit log and in the logs.
+	Redacted = "[REDACTED]"
+)
+
+var ErrAuditNotSupported = &Err{Type: Unknown, Code: "audit_not_supported", Message: "storage doesn't support audited changes"}
+
+//go:generate mockgen -package mock -mock_names AuditService=AuditService -destination mock/auditsvc.go github.com/guilherme-santos/user AuditService
+
+// AuditService is an interface which implements reading the audit log of users.
+type AuditService interface {
+	// AuditLog retrieves the audit entries of a user, most recent first.
+	AuditLog(_ context.Context, userID string, opts *AuditListOptions) (*AuditListResponse, error)
+}
+
+//go:generate mockgen -package mock -mock_names AuditStorage=AuditStorage -destination mock/auditstorage.go github.com/guilherme-santos/user AuditStorage
+
+// AuditStorage is an interface which implements the storage of the audit log.
+// It's append-only, entries can't be changed or removed.
+type AuditStorage interface {
+	// Append stores a new entry.
+	Append(context.Context, *AuditEntry) error
+	// List retrieves the entries of a user, most recent first.
+	List(_ context.Context, userID string, opts *AuditListOptions) (*AuditListResponse, error)
+}
+
+//go:generate mockgen -package mock -mock_names AuditedStorage=AuditedStorage -destination mock/auditedstorage.go github.com/guilherme-santos/user AuditedStorage
+
+// AuditedStorage is implemented by storages which are able to change a user
+// and append its audit entry within the same transaction, so there's never a
+// change without its entry.
+type AuditedStorage interface {
+	// CreateAudited creates u and appends e, UserID of e is set to the new id.
+	CreateAudited(_ context.Context, u *User, e *AuditEntry) error
+	// UpdateAudited updates u and appends e.
+	UpdateAudited(_ context.Context, u *User, e *AuditEntry) error
+	// DeleteAudited deletes the user with id and appends e.
+	DeleteAudited(_ context.Context, id string, e *AuditEntry) error
+}
+
+// AuditEntry is a single change made in a user.
+type AuditEntry struct {
+	ID        string                 `json:"id"`
+	UserID    string                 `json:"user_id"`
+	Action    string                 `json:"action"`
+	Actor     string                 `json:"actor"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Changes   map[string]AuditChange `json:"changes"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// AuditChange contains the value of a field before and after the change.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditListOptions contains the pagination of the audit log.
+type AuditListOptions struct {
+	PerPage int64
+	Page    int
+}
+
+func NewAuditListOptions() *AuditListOptions {
+	return &AuditListOptions{
+		PerPage: 10,
+	}
+}
+
+// AuditListResponse contains the list of entries returned by AuditLog method.
+type AuditListResponse struct {
+	Total   int64         `json:"total"`
+	PerPage int64         `json:"per_page"`
+	Entries []*AuditEntry `json:"entries"`
+}
+
+// NewAuditEntry returns an entry with the diff between before and after, which
+// can be nil when the user is created or deleted. The actor and request id are
+// taken from ctx. Passwords are never stored, only that they changed.
+func NewAuditEntry(ctx context.Context, action string, before, after *User) *AuditEntry {
+	e := &AuditEntry{
+		Action:    action,
+		Actor:     Actor(ctx),
+		RequestID: RequestID(ctx),
+		Changes:   make(map[string]AuditChange),
+		CreatedAt: time.Now().UTC(),
+	}
+	if e.Actor == "" {
+		e.Actor = AnonymousActor
+	}
+	if after != nil {
+		e.UserID = after.ID
+	} else if before != nil {
+		e.UserID = before.ID
+	}
+
+	for field, value := range auditFields {
+		var change AuditChange
+		if before != nil {
+			change.Before = value(before)
+		}
+		if after != nil {
+			change.After = value(after)
+		}
+		if change.Before != change.After {
+			e.Changes[field] = change
+		}
+	}
+	if after != nil && (after.Password != "" || after.PasswordHash != "") {
+		change := AuditChange{After: Redacted}
+		if before != nil {
+			change.Before = Redacted
+		}
+		e.Changes["password"] = change
+	}
+	return e
+}
+
+var auditFields = map[string]func(*User) interface{}{
+	"first_name": func(u *User) interface{} { return u.FirstName },
+	"last_name":  func(u *User) interface{} { return u.LastName },
+	"nickname":   func(u *User) interface{} { return u.Nickname },
+	"email":      func(u *User) interface{} { return u.Email },
+	"country":    func(u *User) interface{} { return u.Country },
+}
+
+// Make sure ServiceImpl implements AuditService
+var _ AuditService = &ServiceImpl{}
+
+// AuditLog retrieves the audit entries of a user using the pagination on opts.
+func (s ServiceImpl) AuditLog(ctx context.Context, userID string, opts *AuditListOptions) (*AuditListResponse, error) {
+	if opts == nil {
+		opts = NewAuditListOptions()
+	}
+	// Make sure the user exists (deleted users are also returned)
+	_, err := s.storage.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.audit.List(ctx, userID, opts)
+}
@@ -0,0 +1,324 @@
+// Package client implements user.Service calling the HTTP API, so consumers
+// can swap between the in-process and the remote implementation.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guilherme-santos/user"
+)
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	backoff    time.Duration
+	header     http.Header
+}
+
+// Make sure Client implements user.Service
+var _ user.Service = &Client{}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient uses c to perform the requests.
+func WithHTTPClient(c *http.Client) Option {
+	return func(cli *Client) {
+		cli.httpClient = c
+	}
+}
+
+// WithTimeout sets the timeout of each attempt. The HTTP client is copied, so
+// the one given to WithHTTPClient keeps its own timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(cli *Client) {
+		httpClient := *cli.httpClient
+		httpClient.Timeout = d
+		cli.httpClient = &httpClient
+	}
+}
+
+// WithRetries retries idempotent requests (all but Create) up to max times when
+// they fail with a network error or a 5xx, waiting backoff (doubled after each
+// attempt) between them.
+func WithRetries(max int, backoff time.Duration) Option {
+	return func(cli *Client) {
+		cli.maxRetries = max
+		cli.backoff = backoff
+	}
+}
+
+// WithHeader adds a header to every request, e.g. X-Tenant-ID.
+func WithHeader(key, value string) Option {
+	return func(cli *Client) {
+		cli.header.Add(key, value)
+	}
+}
+
+// WithAPIKey authenticates every request with key.
+func WithAPIKey(key string) Option {
+	return WithHeader("X-API-Key", key)
+}
+
+// New returns a client for the service running on baseURL, e.g. http://localhost.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/") + "/v1",
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		maxRetries: 2,
+		backoff:    100 * time.Millisecond,
+		header:     make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Create creates a new user, u is updated with the user returned by the service.
+func (c Client) Create(ctx context.Context, u *user.User) error {
+	var created user.User
+	err := c.do(ctx, http.MethodPost, "/users", nil, u, &created)
+	if err != nil {
+		return err
+	}
+	*u = created
+	return nil
+}
+
+// Update updates a existing user, u is updated with the user returned by the service.
+func (c Client) Update(ctx context.Context, u *user.User) error {
+	var updated user.User
+	err := c.do(ctx, http.MethodPut, "/users/"+url.PathEscape(u.ID), nil, u, &updated)
+	if err != nil {
+		return err
+	}
+	*u = updated
+	return nil
+}
+
+func (c Client) Delete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/users/"+url.PathEscape(id), nil, nil, nil)
+}
+
+func (c Client) Get(ctx context.Context, id string) (*user.User, error) {
+	var u user.User
+	err := c.do(ctx, http.MethodGet, "/users/"+url.PathEscape(id), nil, nil, &u)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (c Client) List(ctx context.Context, opts *user.ListOptions) (*user.ListResponse, error) {
+	if opts == nil {
+		opts = user.NewListOptions()
+	}
+	query := url.Values{}
+	if opts.Country != "" {
+		query.Set("country", opts.Country)
+	}
+	if opts.Sort != "" {
+		query.Set("sort", opts.Sort)
+	}
+	if opts.Cursor != "" {
+		query.Set("cursor", opts.Cursor)
+	}
+	if opts.PerPage > 0 {
+		query.Set("per_page", strconv.FormatInt(opts.PerPage, 10))
+	}
+	if opts.Page > 0 {
+		query.Set("page", strconv.Itoa(opts.Page))
+	}
+
+	var resp user.ListResponse
+	err := c.do(ctx, http.MethodGet, "/users", query, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListAll calls fn for every user matching opts, requesting the next pages
+// while there's more users. opts.Page and opts.Cursor are used as start point.
+func (c Client) ListAll(ctx context.Context, opts *user.ListOptions, fn func(*user.User) error) error {
+	if opts == nil {
+		opts = user.NewListOptions()
+	}
+	next := *opts
+
+	for {
+		resp, err := c.List(ctx, &next)
+		if err != nil {
+			return err
+		}
+		for _, u := range resp.Users {
+			err = fn(u)
+			if err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case resp.NextCursor != "":
+			next.Cursor = resp.NextCursor
+		case next.Cursor == "" && int64(len(resp.Users)) >= next.PerPage && len(resp.Users) > 0:
+			next.Page++
+		default:
+			return nil
+		}
+	}
+}
+
+func (c Client) do(ctx context.Context, method, path string, query url.Values, body, v interface{}) error {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	// Only idempotent requests are retried
+	retries := c.maxRetries
+	if method == http.MethodPost {
+		retries = 0
+	}
+	backoff := c.backoff
+
+	for attempt := 0; ; attempt++ {
+		status, err := c.doOnce(ctx, method, u, reqBody, v)
+		if err == nil || attempt >= retries || !retryable(status, err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// doOnce performs a single attempt returning the status code received, or 0
+// when no response was received.
+func (c Client) doOnce(ctx context.Context, method, u string, body []byte, v interface{}) (int, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return 0, err
+	}
+	for k, vv := range c.header {
+		req.Header[k] = vv
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return resp.StatusCode, decodeError(resp)
+	}
+	if v == nil || resp.StatusCode == http.StatusNoContent {
+		return resp.StatusCode, nil
+	}
+	return resp.StatusCode, json.NewDecoder(resp.Body).Decode(v)
+}
+
+// statusError is returned when the service responds with an error without a
+// valid body, e.g. a proxy in front of it.
+type statusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d: %s", e.StatusCode, e.Body)
+}
+
+// decodeError converts the body written by respondWithError back into
+// *user.Error or *user.FieldError.
+func decodeError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	var payload struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Field   string `json:"field"`
+	}
+	err := json.Unmarshal(body, &payload)
+	if err != nil || payload.Message == "" {
+		return &statusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	uerr := user.Error{
+		Type:    errorType(resp.StatusCode),
+		Code:    payload.Code,
+		Message: payload.Message,
+	}
+	if payload.Field != "" {
+		return &user.FieldError{
+			Err:   uerr,
+			Field: payload.Field,
+		}
+	}
+	return &uerr
+}
+
+func errorType(status int) user.Type {
+	switch status {
+	case http.StatusBadRequest:
+		return user.InvalidArgument
+	case http.StatusNotFound:
+		return user.NotFound
+	case http.StatusTooManyRequests:
+		return user.ResourceExhausted
+	case http.StatusUnauthorized:
+		return user.Unauthenticated
+	case http.StatusForbidden:
+		return user.PermissionDenied
+	case http.StatusConflict:
+		return user.Conflict
+	case http.StatusUnprocessableEntity:
+		return user.Unprocessable
+	}
+	return user.Unknown
+}
+
+func retryable(status int, err error) bool {
+	if status != 0 {
+		return status >= 500 || status == http.StatusTooManyRequests
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var nerr net.Error
+	return errors.As(err, &nerr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,147 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/client"
+	uhttp "github.com/guilherme-santos/user/http"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	u := newUser()
+
+	// Creates the user
+	svc := mock.NewUserService(ctrl)
+	svc.EXPECT().
+		Create(gomock.Any(), u).
+		DoAndReturn(func(_ context.Context, u *user.User) error {
+			u.ID = "uuid"
+			return nil
+		})
+	svc.EXPECT().Get(gomock.Any(), "uuid").Return(&user.User{ID: "uuid", Email: u.Email}, nil)
+
+	cli := client.New(newServer(t, svc).URL)
+	err := cli.Create(context.Background(), u)
+	assert.NoError(t, err)
+	assert.Equal(t, &user.User{ID: "uuid", Email: "xguiga@gmail.com"}, u)
+}
+
+func TestClientErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := mock.NewUserService(ctrl)
+	svc.EXPECT().Get(gomock.Any(), "uuid").Return(nil, user.ErrNotFound)
+	svc.EXPECT().Create(gomock.Any(), gomock.Any()).Return(user.NewMissingFieldError("email"))
+
+	cli := client.New(newServer(t, svc).URL)
+
+	_, err := cli.Get(context.Background(), "uuid")
+	assert.Equal(t, user.ErrNotFound, err)
+
+	err = cli.Create(context.Background(), &user.User{})
+	assert.Equal(t, user.NewMissingFieldError("email"), err)
+}
+
+func TestClientRetries(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id":"uuid"}`))
+	}))
+	defer srv.Close()
+
+	cli := client.New(srv.URL, client.WithRetries(2, time.Millisecond))
+	u, err := cli.Get(context.Background(), "uuid")
+	assert.NoError(t, err)
+	assert.Equal(t, "uuid", u.ID)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestClientTimeoutSharedHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{"id":"uuid"}`))
+	}))
+	defer srv.Close()
+
+	// the timeout only applies to this client
+	httpClient := &http.Client{}
+	cli := client.New(srv.URL,
+		client.WithHTTPClient(httpClient),
+		client.WithTimeout(time.Millisecond),
+		client.WithRetries(0, 0),
+	)
+	_, err := cli.Get(context.Background(), "uuid")
+	assert.Error(t, err)
+	assert.Zero(t, httpClient.Timeout)
+}
+
+func TestClientListAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	u1 := newUser()
+	u1.ID = "uuid-1"
+	u2 := newUser()
+	u2.ID = "uuid-2"
+	u3 := newUser()
+	u3.ID = "uuid-3"
+
+	// Retrieves all pages until there's no more users
+	svc := mock.NewUserService(ctrl)
+	gomock.InOrder(
+		svc.EXPECT().
+			List(gomock.Any(), &user.ListOptions{Country: "DE", PerPage: 2}).
+			Return(&user.ListResponse{PerPage: 2, Users: []*user.User{u1, u2}}, nil),
+		svc.EXPECT().
+			List(gomock.Any(), &user.ListOptions{Country: "DE", PerPage: 2, Page: 1}).
+			Return(&user.ListResponse{PerPage: 2, Users: []*user.User{u3}}, nil),
+	)
+
+	var ids []string
+	cli := client.New(newServer(t, svc).URL)
+	err := cli.ListAll(context.Background(), &user.ListOptions{Country: "DE", PerPage: 2}, func(u *user.User) error {
+		ids = append(ids, u.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"uuid-1", "uuid-2", "uuid-3"}, ids)
+}
+
+func newServer(t *testing.T, svc user.Service) *httptest.Server {
+	r := uhttp.NewRouter(nil, nil)
+	r.Route("/v1", func(r chi.Router) {
+		uhttp.NewUserHandler(r, svc)
+	})
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newUser() *user.User {
+	return &user.User{
+		FirstName: "Guilherme",
+		LastName:  "S.",
+		Password:  "123456",
+		Email:     "xguiga@gmail.com",
+		Country:   "DE",
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/guilherme-santos/user"
+)
+
+// apikey manages the API keys used to authenticate in the API, e.g.:
+//
+//	user apikey create -name backoffice -scopes users:read,users:write -expires 720h -tenant brand-1
+//	user apikey revoke c74tbdnblarkcprj54f0
+func apikey(ctx context.Context, svc user.APIKeyService, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: user apikey create|revoke")
+	}
+
+	switch args[0] {
+	case "create":
+		return createAPIKey(ctx, svc, args[1:])
+	case "revoke":
+		if len(args) != 2 {
+			return errors.New("usage: user apikey revoke <id>")
+		}
+		return svc.RevokeAPIKey(ctx, args[1])
+	}
+	return fmt.Errorf("unknown apikey command %q", args[0])
+}
+
+// createAPIKey prints the key created with its secret, which can't be
+// retrieved later.
+func createAPIKey(ctx context.Context, svc user.APIKeyService, args []string) error {
+	fs := flag.NewFlagSet("apikey create", flag.ExitOnError)
+	name := fs.String("name", "", "name of the key, it's used as actor in the audit log")
+	scopes := fs.String("scopes", user.ScopeUsersRead, "comma separated list of scopes: "+strings.Join(user.Scopes, ", "))
+	expires := fs.Duration("expires", 0, "time until the key expires, it never expires if zero")
+	tenant := fs.String("tenant", "", "tenant the key is bound to, it can access any tenant if empty")
+	fs.Parse(args)
+
+	k := &user.APIKey{
+		Name:     *name,
+		Scopes:   strings.Split(*scopes, ","),
+		TenantID: *tenant,
+	}
+	if *expires > 0 {
+		expiresAt := time.Now().Add(*expires).UTC().Truncate(time.Second)
+		k.ExpiresAt = &expiresAt
+	}
+	secret, err := svc.CreateAPIKey(ctx, k)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(os.Stdout).Encode(struct {
+		*user.APIKey
+		Secret string `json:"secret"`
+	}{k, secret})
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/guilherme-santos/user/ratelimit"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+// envPrefix is the prefix of all env vars of the configuration.
+const envPrefix = "usersvc"
+
+// config is all configuration from the env, fields tagged with redact are
+// secrets which aren't printed.
+type config struct {
+	Logger struct {
+		Level string `envconfig:"LEVEL" default:"info"`
+		// Format is one of text or json
+		Format string `envconfig:"FORMAT" default:"text"`
+		// RedactFields are the fields whose value is replaced, e.g. personal data
+		RedactFields []string `envconfig:"REDACT_FIELDS" default:"email,password,password_hash,first_name,last_name"`
+	} `envconfig:"LOGGER"`
+	HTTP struct {
+		Addr              string        `envconfig:"ADDR" default:"0.0.0.0:80"`
+		ReadHeaderTimeout time.Duration `envconfig:"READ_HEADER_TIMEOUT" default:"2s"`
+		// ReadTimeout and WriteTimeout don't apply to import and export
+		ReadTimeout    time.Duration `envconfig:"READ_TIMEOUT" default:"5s"`
+		WriteTimeout   time.Duration `envconfig:"WRITE_TIMEOUT" default:"10s"`
+		IdleTimeout    time.Duration `envconfig:"IDLE_TIMEOUT" default:"30s"`
+		MaxHeaderBytes int           `envconfig:"MAX_HEADER_BYTES" default:"1048576"`
+		// MaxBodyBytes doesn't apply to import, zero disables it
+		MaxBodyBytes int64 `envconfig:"MAX_BODY_BYTES" default:"1048576"`
+		KeepAlive    bool  `envconfig:"KEEP_ALIVE" default:"true"`
+		TLS          struct {
+			// CertFile enables tls, files are reloaded on change or SIGHUP
+			CertFile string `envconfig:"CERT_FILE"`
+			KeyFile  string `envconfig:"KEY_FILE"`
+			// ClientCAFile enables mTLS
+			ClientCAFile      string `envconfig:"CLIENT_CA_FILE"`
+			RequireClientCert bool   `envconfig:"REQUIRE_CLIENT_CERT" default:"false"`
+		} `envconfig:"TLS"`
+		// ValidateRequests validates every request against the OpenAPI spec
+		ValidateRequests bool `envconfig:"VALIDATE_REQUESTS" default:"false"`
+		// TrustedProxies are the CIDRs whose X-Forwarded-For and X-Real-IP
+		// headers are trusted, e.g. 10.0.0.0/8
+		TrustedProxies []string `envconfig:"TRUSTED_PROXIES"`
+	} `envconfig:"HTTP"`
+	Auth struct {
+		// Enabled requires an API key in every request to /v1 and grpc
+		Enabled bool `envconfig:"ENABLED" default:"true"`
+	} `envconfig:"AUTH"`
+	Token struct {
+		// KeysDir keeps the signing keys, it must be shared by all instances
+		KeysDir    string        `envconfig:"KEYS_DIR" default:"keys"`
+		Issuer     string        `envconfig:"ISSUER" default:"usersvc"`
+		AccessTTL  time.Duration `envconfig:"ACCESS_TTL" default:"15m"`
+		RefreshTTL time.Duration `envconfig:"REFRESH_TTL" default:"720h"`
+		// KeyRotation is how often a new signing key is created
+		KeyRotation time.Duration `envconfig:"KEY_ROTATION" default:"168h"`
+	} `envconfig:"TOKEN"`
+	RateLimit struct {
+		// Store is one of memory or redis
+		Store     string `envconfig:"STORE" default:"memory"`
+		RedisAddr string `envconfig:"REDIS_ADDR" default:"localhost:6379"`
+		// IP and APIKey are the limits of each client, e.g. 100/s, empty disables it
+		IP     ratelimit.Limit  `envconfig:"IP" default:"600/m"`
+		APIKey ratelimit.Limit  `envconfig:"API_KEY" default:"6000/m"`
+		Routes ratelimit.Limits `envconfig:"ROUTES" default:"POST /v1/users=10/m,POST /v1/users:import=10/m,POST /v1/auth/token=10/m"`
+	} `envconfig:"RATELIMIT"`
+	Idempotency struct {
+		// TTL is how long responses are kept to be replayed
+		TTL time.Duration `envconfig:"TTL" default:"24h"`
+		// Store is one of memory or redis
+		Store     string `envconfig:"STORE" default:"memory"`
+		RedisAddr string `envconfig:"REDIS_ADDR" default:"localhost:6379"`
+	} `envconfig:"IDEMPOTENCY"`
+	Events struct {
+		// Public publishes only the public fields of users, for brokers with
+		// less-trusted subscribers
+		Public bool `envconfig:"PUBLIC" default:"false"`
+	} `envconfig:"EVENTS"`
+	GRPC struct {
+		Addr string `envconfig:"ADDR" default:"0.0.0.0:9090"`
+	} `envconfig:"GRPC"`
+	Health struct {
+		// Timeout is how long each check has
+		Timeout time.Duration `envconfig:"TIMEOUT" default:"1s"`
+		// CacheTTL is how long results are reused between probes
+		CacheTTL time.Duration `envconfig:"CACHE_TTL" default:"2s"`
+	} `envconfig:"HEALTH"`
+	Shutdown struct {
+		// DrainPeriod is how long readiness fails before the servers stop,
+		// so load balancers stop sending new requests
+		DrainPeriod time.Duration `envconfig:"DRAIN_PERIOD" default:"5s"`
+		// Timeout is how long pending requests, events and queries have to
+		// finish
+		Timeout time.Duration `envconfig:"TIMEOUT" default:"20s"`
+	} `envconfig:"SHUTDOWN"`
+	Tracing struct {
+		// Exporter is one of none, stdout or otlp
+		Exporter     string  `envconfig:"EXPORTER" default:"none"`
+		OTLPEndpoint string  `envconfig:"OTLP_ENDPOINT" default:"localhost:4317"`
+		OTLPInsecure bool    `envconfig:"OTLP_INSECURE" default:"true"`
+		SampleRatio  float64 `envconfig:"SAMPLE_RATIO" default:"1"`
+	} `envconfig:"TRACING"`
+	// Storage is one of mysql, postgres or sqlite, only its env vars are
+	// required
+	Storage string `envconfig:"STORAGE" default:"mysql"`
+	MySQL   struct {
+		Host     string `envconfig:"HOST"`
+		Port     int    `envconfig:"PORT" default:"3306"`
+		User     string `envconfig:"USER"`
+		Password string `envconfig:"PASSWORD" redact:"true"`
+		Database string `envconfig:"DATABASE" default:"user"`
+		// AutoMigrate applies the migrations when the server starts,
+		// otherwise they're applied by user migrate up
+		AutoMigrate bool `envconfig:"AUTO_MIGRATE" default:"false"`
+		// MigrationLockTimeout is how long to wait for another instance
+		// migrating
+		MigrationLockTimeout time.Duration `envconfig:"MIGRATION_LOCK_TIMEOUT" default:"1m"`
+		// SlowQueryThreshold logs queries taking longer, zero disables it
+		SlowQueryThreshold time.Duration `envconfig:"SLOW_QUERY_THRESHOLD" default:"200ms"`
+		// ExplainSlowQueries logs the plan of slow List queries as debug
+		ExplainSlowQueries bool `envconfig:"EXPLAIN_SLOW_QUERIES" default:"false"`
+	} `envconfig:"MYSQL"`
+	Postgres struct {
+		Host     string `envconfig:"HOST"`
+		Port     int    `envconfig:"PORT" default:"5432"`
+		User     string `envconfig:"USER"`
+		Password string `envconfig:"PASSWORD" redact:"true"`
+		Database string `envconfig:"DATABASE" default:"user"`
+		// SSLMode is one of disable, require, verify-ca or verify-full
+		SSLMode string `envconfig:"SSLMODE" default:"disable"`
+		// AutoMigrate applies the migrations when the server starts,
+		// otherwise they're applied by user migrate up
+		AutoMigrate bool `envconfig:"AUTO_MIGRATE" default:"false"`
+		// MigrationLockTimeout is how long to wait for another instance
+		// migrating
+		MigrationLockTimeout time.Duration `envconfig:"MIGRATION_LOCK_TIMEOUT" default:"1m"`
+	} `envconfig:"POSTGRES"`
+	SQLite struct {
+		// Path is the database file, it's created if it doesn't exist
+		Path string `envconfig:"PATH" default:"user.db"`
+		// AutoMigrate applies the migrations when the server starts, there's
+		// a single instance using the file
+		AutoMigrate bool `envconfig:"AUTO_MIGRATE" default:"true"`
+	} `envconfig:"SQLITE"`
+}
+
+// requiredEnv are the env vars required by each storage.
+var requiredEnv = map[string][]string{
+	"mysql":    {"USERSVC_MYSQL_HOST", "USERSVC_MYSQL_USER", "USERSVC_MYSQL_PASSWORD"},
+	"postgres": {"USERSVC_POSTGRES_HOST", "USERSVC_POSTGRES_USER", "USERSVC_POSTGRES_PASSWORD"},
+	"sqlite":   {},
+}
+
+func loadConfig() (*config, error) {
+	var cfg config
+	err := envconfig.Process(envPrefix, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	required, ok := requiredEnv[cfg.Storage]
+	if !ok {
+		return nil, fmt.Errorf("unknown storage %q", cfg.Storage)
+	}
+	// like the required tag, the env var must be set but it can be empty
+	for _, name := range required {
+		if _, ok := os.LookupEnv(name); !ok {
+			return nil, fmt.Errorf("required key %s missing value", name)
+		}
+	}
+	return &cfg, nil
+}
+
+// printConfigFormat is the template of envconfig.Usagef, it prints the value
+// of each env var.
+const printConfigFormat = `{{range .}}{{usage_key .}}	{{if .Tags.Get "redact"}}[REDACTED]{{else}}{{.Field}}{{end}}
+{{end}}`
+
+// configCmd prints the configuration in use, secrets are redacted, e.g.:
+//
+//	user config print
+func configCmd(_ context.Context, cfg *config, args []string) error {
+	if len(args) != 1 || args[0] != "print" {
+		return errors.New("usage: user config print")
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	err := envconfig.Usagef(envPrefix, cfg, w, printConfigFormat)
+	if err != nil {
+		return fmt.Errorf("unable to print config: %w", err)
+	}
+	return w.Flush()
+}
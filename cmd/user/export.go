@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/guilherme-santos/user"
+)
+
+// export writes all users into a file (or stdout) using the same encoders of
+// GET /v1/users:export, e.g.:
+//
+//	user export -tenant brand-1 -format csv -fields id,email -country DE -o users.csv
+func export(ctx context.Context, svc user.ExportService, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "ndjson", "output format: ndjson or csv")
+	fields := fs.String("fields", "", "comma separated list of fields, all fields if empty")
+	country := fs.String("country", "", "export only users from this country")
+	sort := fs.String("sort", "", "sort users by this field, prefix with - for descending")
+	output := fs.String("o", "-", "output file, - for stdout")
+	tenant := fs.String("tenant", user.DefaultTenant, "export only users from this tenant")
+	fs.Parse(args)
+
+	ctx, err := withTenant(ctx, *tenant)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+
+	var fieldList []string
+	if *fields != "" {
+		fieldList = strings.Split(*fields, ",")
+	}
+	enc, err := user.NewExportEncoder(bw, *format, fieldList)
+	if err != nil {
+		return err
+	}
+
+	opts := user.NewListOptions()
+	opts.Country = *country
+	opts.Sort = *sort
+
+	err = svc.Export(ctx, opts, enc.Encode)
+	if err != nil {
+		return err
+	}
+	err = enc.Flush()
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
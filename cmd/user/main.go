@@ -1,104 +1,140 @@
+// user runs the User Service and the commands to operate it, e.g.:
+//
+//	user serve
+//	user migrate status
+//	user user get c74tbdnblarkcprj54f0
+//	user apikey create -name backoffice -scopes users:read
+//	user config print
+//
+// All of them are configured by the same env vars, see user config print.
 package main
 
 import (
 	"context"
+	"fmt"
 	"os"
-	"os/signal"
+	"sort"
 
 	"github.com/guilherme-santos/user"
-	"github.com/guilherme-santos/user/http"
-	"github.com/guilherme-santos/user/mysql"
-	"github.com/guilherme-santos/user/stub"
+	"github.com/guilherme-santos/user/tracing"
 
-	"github.com/go-chi/chi/v5"
-	"github.com/kelseyhightower/envconfig"
 	"github.com/sirupsen/logrus"
 )
 
-// cfg is all configuration from the env
-var cfg struct {
-	Logger struct {
-		Level string `envconfig:"LEVEL" default:"info"`
-	} `envconfig:"LOGGER"`
-	HTTP struct {
-		Addr string `envconfig:"ADDR" default:"0.0.0.0:80"`
-	} `envconfig:"HTTP"`
-	MySQL struct {
-		Host         string `envconfig:"HOST" required:"true"`
-		Port         int    `envconfig:"PORT" default:"3306"`
-		User         string `envconfig:"USER" required:"true"`
-		Password     string `envconfig:"PASSWORD" required:"true"`
-		Database     string `envconfig:"DATABASE" default:"user"`
-		MigrationDir string `envconfig:"MIGRATION_DIR" default:"mysql/migrations"`
-	} `envconfig:"MYSQL"`
+type command struct {
+	usage string
+	run   func(_ context.Context, _ *config, args []string) error
 }
 
-func init() {
-	envconfig.MustProcess("usersvc", &cfg)
+var commands = map[string]command{
+	"serve": {
+		usage: "run the http and grpc servers, it's the default command",
+		run:   serve,
+	},
+	"migrate": {
+		usage: "up|down|status|force, manage the database schema",
+		run:   migrateCmd,
+	},
+	"user": {
+		usage: "create|get|delete, manage users",
+		run:   withServices(userCmd),
+	},
+	"apikey": {
+		usage: "create|revoke, manage the API keys",
+		run: withServices(func(ctx context.Context, s *services, args []string) error {
+			return apikey(ctx, s.keys, args)
+		}),
+	},
+	"export": {
+		usage: "export users as NDJSON or CSV",
+		run: withServices(func(ctx context.Context, s *services, args []string) error {
+			return export(ctx, s.users, args)
+		}),
+	},
+	"config": {
+		usage: "print, print the configuration with secrets redacted",
+		run:   configCmd,
+	},
 }
 
 func main() {
-	log := logrus.New()
-	logLevel, err := logrus.ParseLevel(cfg.Logger.Level)
-	if err != nil {
-		logLevel = logrus.InfoLevel
-		log.WithError(err).Error("unable to set log level")
-	} else {
-		log.SetLevel(logLevel)
-		log.WithField("log_level", logLevel).Info("log level updated")
+	name, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		usage()
+		os.Exit(2)
 	}
 
-	db, err := mysql.NewConnection(
-		cfg.MySQL.Host,
-		cfg.MySQL.Port,
-		cfg.MySQL.User,
-		cfg.MySQL.Password,
-		cfg.MySQL.Database,
-		cfg.MySQL.MigrationDir,
-	)
+	cfg, err := loadConfig()
 	if err != nil {
-		log.WithError(err).Fatal("unable to connect to database")
+		logrus.WithError(err).Fatal("unable to load config")
 	}
-	log.Info("connected to database")
 
-	userstorage := mysql.NewUserStorage(db)
-	// usercache is just an example where we could add a cache layer
-	// without impact the rest of the code base.
-	// this implementation is empty and do not cache anything.
-	usercache := stub.NewUserStorageCache(userstorage)
+	log := newLogger(cfg)
 
-	// eventsvc is a empty implementation, it doesn't publish any event
-	// but it logs them as debug (make sure to export USERSVC_LOGGER_LEVEL=debug)
-	eventsvc := stub.NewEventService()
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:     cfg.Tracing.Exporter,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		OTLPInsecure: cfg.Tracing.OTLPInsecure,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+		ServiceName:  "usersvc",
+	})
+	if err != nil {
+		log.WithError(err).Fatal("unable to setup tracing")
+	}
 
-	usersvc := user.NewService(usercache, eventsvc)
+	ctx := user.SetLogger(context.Background(), log)
+	if name != "serve" {
+		// changes made by the CLI are recorded in the audit log by who ran it
+		ctx = user.SetActor(ctx, cliActor())
+	}
+	err = cmd.run(ctx, cfg, args)
+	shutdownTracing(context.Background())
+	if err != nil {
+		log.WithError(err).Fatalf("unable to run %s", name)
+	}
+}
 
-	httprouter := http.NewRouter(log)
-	// Add healthcheck handler
-	http.NewHealthHandler(httprouter, db)
-	// Add the user handler
-	httprouter.Route("/v1", func(r chi.Router) {
-		http.NewUserHandler(r, usersvc)
-	})
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-	httpsrv := http.NewServer(cfg.HTTP.Addr, httprouter)
-	log.WithField("addr", cfg.HTTP.Addr).Info("running http server")
+	fmt.Fprintln(os.Stderr, "usage: user <command> [args]")
+	fmt.Fprintln(os.Stderr)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", name, commands[name].usage)
+	}
+}
 
-	errCh := make(chan error)
-	go func() {
-		defer close(errCh)
-		errCh <- httpsrv.ListenAndServe()
-	}()
+func newLogger(cfg *config) *logrus.Logger {
+	log := logrus.New()
+	formatter, err := user.NewLogFormatter(cfg.Logger.Format, cfg.Logger.RedactFields)
+	if err != nil {
+		log.WithError(err).Fatal("unable to set log format")
+	}
+	log.SetFormatter(formatter)
 
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt)
+	logLevel, err := logrus.ParseLevel(cfg.Logger.Level)
+	if err != nil {
+		logLevel = logrus.InfoLevel
+		log.WithError(err).Error("unable to set log level")
+	} else {
+		log.SetLevel(logLevel)
+		log.WithField("log_level", logLevel).Info("log level updated")
+	}
+	return log
+}
 
-	select {
-	case err := <-errCh:
-		log.WithError(err).Error("unable to run http server")
-	case sig := <-sigCh:
-		log.WithField("signal", sig).Warn("signal received, shuting down")
-		// gracefullt shutdown http server
-		httpsrv.Shutdown(context.Background())
+// cliActor identifies who runs the command.
+func cliActor() string {
+	if name := os.Getenv("USER"); name != "" {
+		return "cli:" + name
 	}
+	return "cli"
 }
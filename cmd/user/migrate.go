@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/guilherme-santos/user"
+)
+
+// migrateCmd manages the schema of the database, e.g.:
+//
+//	user migrate up
+//	user migrate down -steps 2
+//	user migrate status
+//	user migrate force 20261019140000
+func migrateCmd(ctx context.Context, cfg *config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: user migrate up|down|status|force")
+	}
+
+	st, err := openStorages(cfg)
+	if err != nil {
+		return err
+	}
+	defer st.db.Close()
+
+	m, err := st.newMigrator()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	switch args[0] {
+	case "up":
+		err = m.Up(ctx)
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ExitOnError)
+		steps := fs.Int("steps", 1, "number of migrations to roll back")
+		fs.Parse(args[1:])
+		err = m.Down(ctx, *steps)
+	case "status":
+		// status is printed at the end
+	case "force":
+		if len(args) != 2 {
+			return errors.New("usage: user migrate force <version>")
+		}
+		version, perr := strconv.Atoi(args[1])
+		if perr != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		err = m.Force(ctx, version)
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+	user.Logger(ctx).WithField("version", status.Version).Info("schema migrated")
+	return json.NewEncoder(os.Stdout).Encode(status)
+}
+
+// migrateUp applies the migrations not applied yet.
+func migrateUp(ctx context.Context, st *storages) error {
+	m, err := st.newMigrator()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	return m.Up(ctx)
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"net/netip"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/grpc"
+	"github.com/guilherme-santos/user/health"
+	"github.com/guilherme-santos/user/http"
+	"github.com/guilherme-santos/user/idempotency"
+	"github.com/guilherme-santos/user/keyring"
+	"github.com/guilherme-santos/user/lifecycle"
+	"github.com/guilherme-santos/user/metrics"
+	"github.com/guilherme-santos/user/ratelimit"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// serve runs the http and grpc servers until SIGTERM or SIGINT, e.g.:
+//
+//	user serve
+func serve(ctx context.Context, cfg *config, args []string) error {
+	log := user.Logger(ctx)
+
+	s, err := newServices(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	st := s.storages
+	if st.autoMigrate {
+		err = migrateUp(ctx, st)
+		if err != nil {
+			return fmt.Errorf("unable to apply migrations: %w", err)
+		}
+	}
+	// the schema must have all migrations the binary expects
+	err = st.checkMigrations(ctx, st.db)
+	if err != nil {
+		return fmt.Errorf("%w, run user migrate up", err)
+	}
+
+	err = metrics.RegisterDBStats(st.db, st.database)
+	if err != nil {
+		return fmt.Errorf("unable to register database metrics: %w", err)
+	}
+
+	signingkeys, err := keyring.Open(cfg.Token.KeysDir)
+	if err != nil {
+		return fmt.Errorf("unable to open signing keys: %w", err)
+	}
+	tokensvc := user.NewTokenService(
+		st.users,
+		st.users,
+		st.tokens,
+		signingkeys,
+		&user.TokenOptions{
+			Issuer:     cfg.Token.Issuer,
+			AccessTTL:  cfg.Token.AccessTTL,
+			RefreshTTL: cfg.Token.RefreshTTL,
+		},
+	)
+
+	var trustedProxies []netip.Prefix
+	for _, cidr := range cfg.HTTP.TrustedProxies {
+		p, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid trusted proxy: %w", err)
+		}
+		trustedProxies = append(trustedProxies, p.Masked())
+	}
+
+	var validate func(nethttp.Handler) nethttp.Handler
+	if cfg.HTTP.ValidateRequests {
+		validate, err = http.ValidateRequests()
+		if err != nil {
+			return fmt.Errorf("unable to load openapi spec: %w", err)
+		}
+	}
+
+	// authentication is disabled when keys is nil
+	var keys user.APIKeyService
+	if cfg.Auth.Enabled {
+		keys = s.keys
+	} else {
+		log.Warn("authentication is disabled, the API is open to anyone")
+	}
+
+	var limitstore ratelimit.Store
+	switch cfg.RateLimit.Store {
+	case "memory":
+		limitstore = ratelimit.NewMemoryStore()
+	case "redis":
+		limitstore = ratelimit.NewRedisStore(redis.NewClient(&redis.Options{
+			Addr: cfg.RateLimit.RedisAddr,
+		}), "usersvc:ratelimit:")
+	default:
+		return fmt.Errorf("unknown rate limit store %q", cfg.RateLimit.Store)
+	}
+
+	var idemstore idempotency.Store
+	switch cfg.Idempotency.Store {
+	case "memory":
+		idemstore = idempotency.NewMemoryStore()
+	case "redis":
+		idemstore = idempotency.NewRedisStore(redis.NewClient(&redis.Options{
+			Addr: cfg.Idempotency.RedisAddr,
+		}), "usersvc:idempotency:")
+	default:
+		return fmt.Errorf("unknown idempotency store %q", cfg.Idempotency.Store)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// old keys are kept until the tokens signed by them expire
+	go signingkeys.RotateEvery(ctx, cfg.Token.KeyRotation, 2*cfg.Token.AccessTTL)
+
+	lc := lifecycle.New(log, cfg.Shutdown.DrainPeriod, cfg.Shutdown.Timeout)
+
+	checks := health.NewRegistry(cfg.Health.CacheTTL)
+	checks.Register(health.Check{
+		Name:     st.name,
+		Run:      st.db.PingContext,
+		Timeout:  cfg.Health.Timeout,
+		Critical: true,
+	})
+	checks.Register(health.Check{
+		Name: "migrations",
+		Run: func(ctx context.Context) error {
+			return st.checkMigrations(ctx, st.db)
+		},
+		Timeout:  cfg.Health.Timeout,
+		Critical: true,
+	})
+	// users are still served without cache and events, but slower or
+	// without publishing changes
+	checks.Register(health.Check{
+		Name:    "cache",
+		Run:     s.usercache.Ping,
+		Timeout: cfg.Health.Timeout,
+	})
+	checks.Register(health.Check{
+		Name:    "events",
+		Run:     s.publisher.Ping,
+		Timeout: cfg.Health.Timeout,
+	})
+
+	httprouter := http.NewRouter(log, trustedProxies)
+	http.NewAPI(httprouter, http.APIConfig{
+		Users:          s.users,
+		Roles:          s.roles,
+		Tokens:         tokensvc,
+		APIKeys:        keys,
+		SigningKeys:    signingkeys,
+		Health:         checks,
+		Ready:          lc.Ready,
+		RateLimitStore: limitstore,
+		RateLimit: http.RateLimitConfig{
+			IP:     cfg.RateLimit.IP,
+			APIKey: cfg.RateLimit.APIKey,
+			Routes: cfg.RateLimit.Routes,
+		},
+		IdempotencyStore: idemstore,
+		IdempotencyTTL:   cfg.Idempotency.TTL,
+		Validate:         validate,
+	})
+
+	httpsrv := http.NewServer(http.ServerConfig{
+		Addr:              cfg.HTTP.Addr,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+		MaxHeaderBytes:    cfg.HTTP.MaxHeaderBytes,
+		MaxBodyBytes:      cfg.HTTP.MaxBodyBytes,
+		KeepAlive:         cfg.HTTP.KeepAlive,
+	}, httprouter)
+
+	tlscfg := http.TLSConfig{
+		CertFile:          cfg.HTTP.TLS.CertFile,
+		KeyFile:           cfg.HTTP.TLS.KeyFile,
+		ClientCAFile:      cfg.HTTP.TLS.ClientCAFile,
+		RequireClientCert: cfg.HTTP.TLS.RequireClientCert,
+	}
+	if tlscfg.Enabled() {
+		certs, err := http.NewCertReloader(tlscfg)
+		if err != nil {
+			return fmt.Errorf("unable to load tls certificate: %w", err)
+		}
+		httpsrv.TLSConfig = certs.TLSConfig()
+		go certs.WatchEvery(ctx, 10*time.Second)
+
+		hupCh := make(chan os.Signal, 1)
+		signal.Notify(hupCh, syscall.SIGHUP)
+		go func() {
+			for range hupCh {
+				err := certs.Reload()
+				if err != nil {
+					log.WithError(err).Error("unable to reload tls certificate")
+					continue
+				}
+				log.Info("tls certificate reloaded")
+			}
+		}()
+	}
+	log.WithFields(logrus.Fields{
+		"addr": cfg.HTTP.Addr,
+		"tls":  tlscfg.Enabled(),
+	}).Info("running http server")
+
+	errCh := make(chan error, 2)
+	go func() {
+		var err error
+		if tlscfg.Enabled() {
+			// certificates come from httpsrv.TLSConfig
+			err = httpsrv.ListenAndServeTLS("", "")
+		} else {
+			err = httpsrv.ListenAndServe()
+		}
+		if err != nil && err != nethttp.ErrServerClosed {
+			errCh <- fmt.Errorf("http server: %w", err)
+		}
+	}()
+
+	// grpc server is disabled when addr is empty
+	grpcsrv := grpc.NewServer(log, s.users, keys)
+	if cfg.GRPC.Addr != "" {
+		lis, err := net.Listen("tcp", cfg.GRPC.Addr)
+		if err != nil {
+			return fmt.Errorf("unable to listen grpc addr: %w", err)
+		}
+		log.WithField("addr", cfg.GRPC.Addr).Info("running grpc server")
+
+		go func() {
+			err := grpcsrv.Serve(lis)
+			if err != nil {
+				errCh <- fmt.Errorf("grpc server: %w", err)
+			}
+		}()
+	}
+
+	// servers stop first, so nothing else is used by requests
+	lc.OnShutdown("http", httpsrv.Shutdown)
+	lc.OnShutdown("grpc", func(ctx context.Context) error {
+		return grpc.Shutdown(ctx, grpcsrv)
+	})
+	lc.OnShutdown("background", func(context.Context) error {
+		cancel()
+		return nil
+	})
+	lc.OnShutdown("events", s.publisher.Flush)
+	lc.OnShutdown(st.name, func(context.Context) error {
+		return st.db.Close()
+	})
+	return lc.Wait(errCh)
+}
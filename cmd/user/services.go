@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/metrics"
+	"github.com/guilherme-santos/user/rbac"
+	"github.com/guilherme-santos/user/stub"
+	"github.com/guilherme-santos/user/tracing"
+)
+
+// services are the storages and services shared by all commands, so the CLI
+// behaves the same as the API, e.g. it records the audit log and publishes
+// the events.
+type services struct {
+	storages  *storages
+	usercache *stub.UserStorageCache
+	publisher *stub.EventService
+	users     *tracing.UserService
+	roles     user.RoleService
+	keys      *user.APIKeyServiceImpl
+}
+
+func newServices(ctx context.Context, cfg *config) (*services, error) {
+	st, err := openStorages(cfg)
+	if err != nil {
+		return nil, err
+	}
+	user.Logger(ctx).WithField("storage", st.name).Info("connected to database")
+
+	userstorage := tracing.NewUserStorage(metrics.NewUserStorage(st.users), st.name)
+	// usercache is just an example where we could add a cache layer
+	// without impact the rest of the code base.
+	// this implementation is empty and do not cache anything.
+	usercache := stub.NewUserStorageCache(userstorage)
+
+	// eventsvc is a empty implementation, it doesn't publish any event
+	// but it logs them as debug (make sure to export USERSVC_LOGGER_LEVEL=debug)
+	publisher := stub.NewEventService()
+	var eventsvc user.EventService = publisher
+	if cfg.Events.Public {
+		eventsvc = user.NewProjectedEventService(eventsvc, user.PublicProjection)
+	}
+	eventsvc = tracing.NewEventService(metrics.NewEventService(eventsvc))
+
+	// rbac only checks users authenticated by access tokens, the ones
+	// authenticated by API keys are limited by their scopes.
+	usersvc := tracing.NewUserService(metrics.NewUserService(rbac.NewUserService(user.NewService(usercache, st.tokens, eventsvc, st.audit), st.roles)))
+	rolesvc := rbac.NewRoleService(user.NewRoleService(st.roles, usercache, eventsvc, st.audit), st.roles)
+
+	return &services{
+		storages:  st,
+		usercache: usercache,
+		publisher: publisher,
+		users:     usersvc,
+		roles:     rolesvc,
+		keys:      user.NewAPIKeyService(st.keys),
+	}, nil
+}
+
+// Close flushes the events and closes the database.
+func (s *services) Close(ctx context.Context) error {
+	err := s.publisher.Flush(ctx)
+	if err != nil {
+		return err
+	}
+	return s.storages.db.Close()
+}
+
+// withServices returns a command which runs fn with the services.
+func withServices(fn func(context.Context, *services, []string) error) func(context.Context, *config, []string) error {
+	return func(ctx context.Context, cfg *config, args []string) error {
+		s, err := newServices(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		defer s.Close(ctx)
+		return fn(ctx, s, args)
+	}
+}
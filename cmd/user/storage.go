@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/mysql"
+	"github.com/guilherme-santos/user/postgres"
+	"github.com/guilherme-santos/user/sqlite"
+	"github.com/guilherme-santos/user/sqlstorage"
+)
+
+// userStorage is implemented by the user storages of all databases.
+type userStorage interface {
+	user.Storage
+	user.AuditedStorage
+	user.BatchStorage
+	user.ExportStorage
+	user.CredentialStorage
+}
+
+// migrator is implemented by the migrators of all databases.
+type migrator interface {
+	Up(context.Context) error
+	Down(_ context.Context, steps int) error
+	Force(_ context.Context, version int) error
+	Status() (*sqlstorage.MigrationStatus, error)
+	Close() error
+}
+
+// storages are the storages of the database selected by USERSVC_STORAGE.
+type storages struct {
+	// name is the database, e.g. mysql, used by metrics, traces and
+	// health checks
+	name     string
+	database string
+	db       *sql.DB
+
+	users  userStorage
+	audit  user.AuditStorage
+	roles  user.RoleStorage
+	keys   user.APIKeyStorage
+	tokens user.RefreshTokenStorage
+
+	// autoMigrate applies the migrations when the server starts
+	autoMigrate     bool
+	newMigrator     func() (migrator, error)
+	checkMigrations func(context.Context, *sql.DB) error
+}
+
+// openStorages connects to the database of cfg.Storage, migrations aren't
+// applied.
+func openStorages(cfg *config) (*storages, error) {
+	switch cfg.Storage {
+	case "mysql":
+		db, err := mysql.NewConnection(
+			cfg.MySQL.Host,
+			cfg.MySQL.Port,
+			cfg.MySQL.User,
+			cfg.MySQL.Password,
+			cfg.MySQL.Database,
+		)
+		if err != nil {
+			return nil, err
+		}
+		instrumenteddb := mysql.NewDB(db,
+			mysql.WithSlowQueryThreshold(cfg.MySQL.SlowQueryThreshold),
+			mysql.WithExplain(cfg.MySQL.ExplainSlowQueries),
+		)
+		return &storages{
+			name:        "mysql",
+			database:    cfg.MySQL.Database,
+			db:          db,
+			users:       mysql.NewUserStorage(instrumenteddb),
+			audit:       mysql.NewAuditStorage(instrumenteddb),
+			roles:       mysql.NewRoleStorage(instrumenteddb),
+			keys:        mysql.NewAPIKeyStorage(instrumenteddb),
+			tokens:      mysql.NewRefreshTokenStorage(instrumenteddb),
+			autoMigrate: cfg.MySQL.AutoMigrate,
+			newMigrator: func() (migrator, error) {
+				m, err := mysql.NewMigrator(db, cfg.MySQL.Database, cfg.MySQL.MigrationLockTimeout)
+				if err != nil {
+					return nil, err
+				}
+				return m, nil
+			},
+			checkMigrations: mysql.CheckMigrations,
+		}, nil
+
+	case "postgres":
+		dsn := postgres.DSN(
+			cfg.Postgres.Host,
+			cfg.Postgres.Port,
+			cfg.Postgres.User,
+			cfg.Postgres.Password,
+			cfg.Postgres.Database,
+			cfg.Postgres.SSLMode,
+		)
+		db, err := postgres.NewConnection(dsn)
+		if err != nil {
+			return nil, err
+		}
+		return &storages{
+			name:        "postgres",
+			database:    cfg.Postgres.Database,
+			db:          db,
+			users:       postgres.NewUserStorage(db),
+			audit:       postgres.NewAuditStorage(db),
+			roles:       postgres.NewRoleStorage(db),
+			keys:        postgres.NewAPIKeyStorage(db),
+			tokens:      postgres.NewRefreshTokenStorage(db),
+			autoMigrate: cfg.Postgres.AutoMigrate,
+			newMigrator: func() (migrator, error) {
+				m, err := postgres.NewMigrator(dsn, cfg.Postgres.MigrationLockTimeout)
+				if err != nil {
+					return nil, err
+				}
+				return m, nil
+			},
+			checkMigrations: postgres.CheckMigrations,
+		}, nil
+
+	case "sqlite":
+		db, err := sqlite.NewConnection(cfg.SQLite.Path)
+		if err != nil {
+			return nil, err
+		}
+		return &storages{
+			name:        "sqlite",
+			database:    cfg.SQLite.Path,
+			db:          db,
+			users:       sqlite.NewUserStorage(db),
+			audit:       sqlite.NewAuditStorage(db),
+			roles:       sqlite.NewRoleStorage(db),
+			keys:        sqlite.NewAPIKeyStorage(db),
+			tokens:      sqlite.NewRefreshTokenStorage(db),
+			autoMigrate: cfg.SQLite.AutoMigrate,
+			newMigrator: func() (migrator, error) {
+				m, err := sqlite.NewMigrator(cfg.SQLite.Path)
+				if err != nil {
+					return nil, err
+				}
+				return m, nil
+			},
+			checkMigrations: sqlite.CheckMigrations,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown storage %q", cfg.Storage)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/guilherme-santos/user"
+)
+
+// userCmd manages users through the same service of the API, so the audit
+// log is recorded and the events are published, e.g.:
+//
+//	echo "$PASSWORD" | user user create -tenant brand-1 -nickname xguiga -email email@gmail.com -country DE -password-stdin
+//	user user get -tenant brand-1 c74tbdnblarkcprj54f0
+//	user user delete -tenant brand-1 c74tbdnblarkcprj54f0
+func userCmd(ctx context.Context, s *services, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: user user create|get|delete")
+	}
+
+	switch args[0] {
+	case "create":
+		return createUser(ctx, s.users, args[1:])
+	case "get", "delete":
+		fs := flag.NewFlagSet("user "+args[0], flag.ExitOnError)
+		tenant := fs.String("tenant", user.DefaultTenant, "tenant of the user")
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: user user %s [-tenant id] <id>", args[0])
+		}
+		ctx, err := withTenant(ctx, *tenant)
+		if err != nil {
+			return err
+		}
+
+		if args[0] == "delete" {
+			return s.users.Delete(ctx, fs.Arg(0))
+		}
+		u, err := s.users.Get(ctx, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		return json.NewEncoder(os.Stdout).Encode(u)
+	}
+	return fmt.Errorf("unknown user command %q", args[0])
+}
+
+// createUser prints the user created.
+func createUser(ctx context.Context, svc user.Service, args []string) error {
+	fs := flag.NewFlagSet("user create", flag.ExitOnError)
+	tenant := fs.String("tenant", user.DefaultTenant, "tenant of the user")
+	firstName := fs.String("first-name", "", "first name of the user")
+	lastName := fs.String("last-name", "", "last name of the user")
+	nickname := fs.String("nickname", "", "nickname of the user")
+	email := fs.String("email", "", "e-mail of the user")
+	country := fs.String("country", "", "country of the user, e.g. DE")
+	password := fs.String("password", "", "password of the user, prefer -password-stdin as it's visible to other processes")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin")
+	fs.Parse(args)
+
+	ctx, err := withTenant(ctx, *tenant)
+	if err != nil {
+		return err
+	}
+	if *passwordStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("unable to read password: %w", err)
+		}
+		*password = strings.TrimRight(line, "\r\n")
+	}
+
+	u := &user.User{
+		FirstName: *firstName,
+		LastName:  *lastName,
+		Nickname:  *nickname,
+		Email:     *email,
+		Country:   *country,
+		Password:  *password,
+	}
+	err = svc.Create(ctx, u)
+	if err != nil {
+		return err
+	}
+	u, err = svc.Get(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(os.Stdout).Encode(u)
+}
+
+// withTenant validates and stores tenant in ctx.
+func withTenant(ctx context.Context, tenant string) (context.Context, error) {
+	err := user.ValidateTenant(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return user.SetTenant(ctx, tenant), nil
+}
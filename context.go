@@ -0,0 +1,88 @@
+package user
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	actorCtx     = contextKey("actor")
+	principalCtx = contextKey("principal")
+	requestIDCtx = contextKey("request_id")
+	apiKeyCtx    = contextKey("api_key")
+	claimsCtx    = contextKey("token_claims")
+)
+
+// SetActor stores who is performing the request, it's used by the audit log.
+func SetActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorCtx, actor)
+}
+
+func Actor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorCtx).(string)
+	return actor
+}
+
+// WithPrincipal returns a copy of ctx which records the principal
+// authenticated down the call chain, so it's known by access logs, which are
+// written by the outermost middleware after the request.
+func WithPrincipal(ctx context.Context) context.Context {
+	return context.WithValue(ctx, principalCtx, new(string))
+}
+
+// SetPrincipal stores the principal authenticated by an API key or access
+// token, it becomes the actor of the request, replacing the one claimed by
+// the request, and is added to the log fields.
+func SetPrincipal(ctx context.Context, principal string) context.Context {
+	if p, ok := ctx.Value(principalCtx).(*string); ok {
+		*p = principal
+	}
+	ctx = SetActor(ctx, principal)
+	return WithLogFields(ctx, logrus.Fields{"principal": principal})
+}
+
+// Principal returns the principal authenticated down the call chain of ctx,
+// which must come from WithPrincipal. It's empty when the request wasn't
+// authenticated.
+func Principal(ctx context.Context) string {
+	p, _ := ctx.Value(principalCtx).(*string)
+	if p == nil {
+		return ""
+	}
+	return *p
+}
+
+func SetRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDCtx, id)
+}
+
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDCtx).(string)
+	return id
+}
+
+// SetAPIKey stores the key which authenticated the request.
+func SetAPIKey(ctx context.Context, k *APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyCtx, k)
+}
+
+// APIKeyFrom returns the key which authenticated the request, nil when the
+// authentication is disabled.
+func APIKeyFrom(ctx context.Context) *APIKey {
+	k, _ := ctx.Value(apiKeyCtx).(*APIKey)
+	return k
+}
+
+// SetTokenClaims stores the claims of the access token which authenticated
+// the request.
+func SetTokenClaims(ctx context.Context, c *TokenClaims) context.Context {
+	return context.WithValue(ctx, claimsCtx, c)
+}
+
+// TokenClaimsFrom returns the claims of the access token which authenticated
+// the request, nil when it wasn't authenticated by one.
+func TokenClaimsFrom(ctx context.Context) *TokenClaims {
+	c, _ := ctx.Value(claimsCtx).(*TokenClaims)
+	return c
+}
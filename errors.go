@@ -46,4 +46,32 @@ const (
 	Unknown Type = iota
 	InvalidArgument
 	NotFound
+	ResourceExhausted
+	Unauthenticated
+	PermissionDenied
+	// Conflict is a request which conflicts with another one in progress.
+	Conflict
+	// Unprocessable is a valid request which can't be processed, e.g. it
+	// doesn't match a previous request.
+	Unprocessable
 )
+
+func (t Type) String() string {
+	switch t {
+	case InvalidArgument:
+		return "invalid_argument"
+	case NotFound:
+		return "not_found"
+	case ResourceExhausted:
+		return "resource_exhausted"
+	case Unauthenticated:
+		return "unauthenticated"
+	case PermissionDenied:
+		return "permission_denied"
+	case Conflict:
+		return "conflict"
+	case Unprocessable:
+		return "unprocessable"
+	}
+	return "unknown"
+}
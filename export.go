@@ -0,0 +1,172 @@
+package user
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var ErrExportNotSupported = &Err{Type: Unknown, Code: "export_not_supported", Message: "storage doesn't support export"}
+
+//go:generate mockgen -package mock -mock_names ExportService=ExportService -destination mock/exportsvc.go github.com/guilherme-santos/user ExportService
+
+// ExportService is an interface which implements the export of users.
+type ExportService interface {
+	// Export calls fn for every user matching the criterias on ListOptions,
+	// pagination is ignored.
+	Export(_ context.Context, opts *ListOptions, fn func(*User) error) error
+}
+
+// ExportStorage is implemented by storages which are able to stream users
+// from a consistent snapshot.
+type ExportStorage ExportService
+
+// Make sure ServiceImpl implements ExportService
+var _ ExportService = &ServiceImpl{}
+
+// Export streams all users using the criteria provided on opts.
+func (s ServiceImpl) Export(ctx context.Context, opts *ListOptions, fn func(*User) error) error {
+	es, ok := s.storage.(ExportStorage)
+	if !ok {
+		return ErrExportNotSupported
+	}
+	if opts == nil {
+		opts = NewListOptions()
+	}
+	err := opts.Validate()
+	if err != nil {
+		return err
+	}
+	return es.Export(ctx, opts, fn)
+}
+
+// ExportFields contains all fields that can be exported, in the default order.
+var ExportFields = []string{
+	"id",
+	"first_name",
+	"last_name",
+	"nickname",
+	"email",
+	"country",
+	"created_at",
+	"updated_at",
+}
+
+var exportValues = map[string]func(*User) interface{}{
+	"id":         func(u *User) interface{} { return u.ID },
+	"first_name": func(u *User) interface{} { return u.FirstName },
+	"last_name":  func(u *User) interface{} { return u.LastName },
+	"nickname":   func(u *User) interface{} { return u.Nickname },
+	"email":      func(u *User) interface{} { return u.Email },
+	"country":    func(u *User) interface{} { return u.Country },
+	"created_at": func(u *User) interface{} { return u.CreatedAt },
+	"updated_at": func(u *User) interface{} { return u.UpdatedAt },
+}
+
+// ExportEncoder writes users in a given format.
+type ExportEncoder interface {
+	Encode(*User) error
+	// Flush writes any buffered data.
+	Flush() error
+}
+
+// NewExportEncoder returns an encoder for format (ndjson or csv) writing only
+// the given fields, all ExportFields are written when fields is empty.
+func NewExportEncoder(w io.Writer, format string, fields []string) (ExportEncoder, error) {
+	if len(fields) == 0 {
+		fields = ExportFields
+	}
+	for _, f := range fields {
+		if _, ok := exportValues[f]; !ok {
+			return nil, &FieldError{
+				Err: Error{
+					Type:    InvalidArgument,
+					Code:    "invalid_export_field",
+					Message: fmt.Sprintf("Field %q can't be exported, use one of: %s", f, strings.Join(ExportFields, ",")),
+				},
+				Field: "fields",
+			}
+		}
+	}
+
+	switch format {
+	case "", "ndjson":
+		return &ndjsonEncoder{enc: json.NewEncoder(w), fields: fields}, nil
+	case "csv":
+		return &csvEncoder{w: csv.NewWriter(w), fields: fields}, nil
+	}
+	return nil, &FieldError{
+		Err: Error{
+			Type:    InvalidArgument,
+			Code:    "invalid_export_format",
+			Message: "Format should be ndjson or csv",
+		},
+		Field: "format",
+	}
+}
+
+type ndjsonEncoder struct {
+	enc    *json.Encoder
+	fields []string
+}
+
+func (e *ndjsonEncoder) Encode(u *User) error {
+	v := make(map[string]interface{}, len(e.fields))
+	for _, f := range e.fields {
+		v[f] = exportValues[f](u)
+	}
+	return e.enc.Encode(v)
+}
+
+func (e *ndjsonEncoder) Flush() error {
+	return nil
+}
+
+type csvEncoder struct {
+	w          *csv.Writer
+	fields     []string
+	headerDone bool
+}
+
+func (e *csvEncoder) Encode(u *User) error {
+	if !e.headerDone {
+		err := e.w.Write(e.fields)
+		if err != nil {
+			return err
+		}
+		e.headerDone = true
+	}
+
+	record := make([]string, len(e.fields))
+	for i, f := range e.fields {
+		switch v := exportValues[f](u).(type) {
+		case string:
+			record[i] = v
+		case time.Time:
+			record[i] = v.Format(time.RFC3339Nano)
+		case *time.Time:
+			if v != nil {
+				record[i] = v.Format(time.RFC3339Nano)
+			}
+		}
+	}
+	return e.w.Write(record)
+}
+
+// Flush writes the header when no user was encoded, so the output is
+// always a valid csv.
+func (e *csvEncoder) Flush() error {
+	if !e.headerDone {
+		err := e.w.Write(e.fields)
+		if err != nil {
+			return err
+		}
+		e.headerDone = true
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
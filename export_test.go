@@ -0,0 +1,75 @@
+package user_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/guilherme-santos/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportEncoder(t *testing.T) {
+	u := newUser()
+	u.ID = "uuid"
+	u.CreatedAt = time.Date(2021, 12, 27, 15, 29, 59, 0, time.UTC)
+
+	testcases := []struct {
+		Name   string
+		Format string
+		Fields []string
+		Output string
+		Error  string
+	}{
+		{
+			Name:   "ndjson",
+			Format: "ndjson",
+			Output: `{"country":"DE","created_at":"2021-12-27T15:29:59Z","email":"xguiga@gmail.com","first_name":"Guilherme","id":"uuid","last_name":"S.","nickname":"","updated_at":null}` + "\n",
+		},
+		{
+			Name:   "ndjson with fields",
+			Format: "ndjson",
+			Fields: []string{"id", "email"},
+			Output: `{"email":"xguiga@gmail.com","id":"uuid"}` + "\n",
+		},
+		{
+			Name:   "csv",
+			Format: "csv",
+			Output: "id,first_name,last_name,nickname,email,country,created_at,updated_at\n" +
+				"uuid,Guilherme,S.,,xguiga@gmail.com,DE,2021-12-27T15:29:59Z,\n",
+		},
+		{
+			Name:   "csv with fields",
+			Format: "csv",
+			Fields: []string{"email", "id"},
+			Output: "email,id\nxguiga@gmail.com,uuid\n",
+		},
+		{
+			Name:   "unknown field",
+			Format: "csv",
+			Fields: []string{"password"},
+			Error:  `code=invalid_export_field field=fields message=Field "password" can't be exported, use one of: id,first_name,last_name,nickname,email,country,created_at,updated_at`,
+		},
+		{
+			Name:   "unknown format",
+			Format: "xml",
+			Error:  "code=invalid_export_format field=format message=Format should be ndjson or csv",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			enc, err := user.NewExportEncoder(buf, tc.Format, tc.Fields)
+			if tc.Error != "" {
+				assert.EqualError(t, err, tc.Error)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, enc.Encode(u))
+			assert.NoError(t, enc.Flush())
+			assert.Equal(t, tc.Output, buf.String())
+		})
+	}
+}
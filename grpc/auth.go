@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/grpc/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// APIKeyMetadata is the metadata equivalent of X-API-Key header.
+const APIKeyMetadata = "x-api-key"
+
+// methodScopes are the scopes required by each method.
+var methodScopes = map[string]string{
+	pb.UserService_Create_FullMethodName: user.ScopeUsersWrite,
+	pb.UserService_Update_FullMethodName: user.ScopeUsersWrite,
+	pb.UserService_Delete_FullMethodName: user.ScopeUsersDelete,
+	pb.UserService_Get_FullMethodName:    user.ScopeUsersRead,
+	pb.UserService_List_FullMethodName:   user.ScopeUsersRead,
+}
+
+// Authenticate returns a unary interceptor which rejects calls without a valid
+// API key in the metadata or whose key doesn't have the scope of the method,
+// like http.Authenticate and http.RequireScope do. Keys bound to a tenant
+// replace the tenant of the call.
+func Authenticate(svc user.APIKeyService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		secret := firstMetadata(md, APIKeyMetadata)
+		if secret == "" {
+			return nil, toStatus(user.ErrUnauthenticated)
+		}
+		k, err := svc.Authenticate(ctx, secret)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		scope, ok := methodScopes[info.FullMethod]
+		if !ok || !k.HasScope(scope) {
+			return nil, toStatus(user.ErrPermissionDenied)
+		}
+
+		if k.TenantID != "" {
+			ctx = withTenant(ctx, k.TenantID)
+		}
+		ctx = user.SetAPIKey(ctx, k)
+		ctx = user.SetPrincipal(ctx, "apikey:"+k.Name)
+		return handler(ctx, req)
+	}
+}
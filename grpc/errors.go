@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"errors"
+
+	"github.com/guilherme-santos/user"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ErrorDomain is the domain of errdetails.ErrorInfo sent with every error.
+const ErrorDomain = "user.guilherme-santos.github.com"
+
+// toStatus converts err into a grpc status error, user.Error.Code travels as
+// errdetails.ErrorInfo and user.FieldError as errdetails.BadRequest.
+func toStatus(err error) error {
+	var uerr *user.Error
+	if !errors.As(err, &uerr) {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	var code codes.Code
+
+	switch uerr.Type {
+	case user.InvalidArgument:
+		code = codes.InvalidArgument
+	case user.NotFound:
+		code = codes.NotFound
+	case user.ResourceExhausted:
+		code = codes.ResourceExhausted
+	case user.Unauthenticated:
+		code = codes.Unauthenticated
+	case user.PermissionDenied:
+		code = codes.PermissionDenied
+	case user.Conflict:
+		code = codes.Aborted
+	case user.Unprocessable:
+		code = codes.FailedPrecondition
+	default:
+		code = codes.Unknown
+	}
+
+	st := status.New(code, uerr.Message)
+	if uerr.Code == "" {
+		return st.Err()
+	}
+
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason: uerr.Code,
+			Domain: ErrorDomain,
+		},
+	}
+	var ferr *user.FieldError
+	if errors.As(err, &ferr) {
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       ferr.Field,
+					Description: ferr.Message,
+				},
+			},
+		})
+	}
+	dst, derr := st.WithDetails(details...)
+	if derr != nil {
+		return st.Err()
+	}
+	return dst.Err()
+}
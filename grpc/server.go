@@ -0,0 +1,241 @@
+package grpc
+
+//go:generate protoc -I proto --go_out=pb --go_opt=paths=source_relative --go-grpc_out=pb --go-grpc_opt=paths=source_relative user.proto
+
+import (
+	"context"
+	"time"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/grpc/pb"
+
+	"github.com/rs/xid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// RequestIDMetadata, ActorMetadata and TenantMetadata are the metadata
+	// equivalents of X-Request-Id, X-Actor and X-Tenant-ID headers.
+	RequestIDMetadata = "x-request-id"
+	ActorMetadata     = "x-actor"
+	TenantMetadata    = "x-tenant-id"
+)
+
+// NewServer returns a grpc server with UserServer registered, calls are
+// authenticated by keys unless it's nil.
+func NewServer(logger logrus.FieldLogger, svc user.Service, keys user.APIKeyService) *grpc.Server {
+	interceptors := []grpc.UnaryServerInterceptor{Logger(logger), Tenant}
+	if keys != nil {
+		interceptors = append(interceptors, Authenticate(keys))
+	}
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors...),
+	)
+	pb.RegisterUserServiceServer(srv, NewUserServer(svc))
+	return srv
+}
+
+// Shutdown stops srv gracefully, waiting for the pending calls until ctx is
+// done, when they're cancelled.
+func Shutdown(ctx context.Context, srv *grpc.Server) error {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		srv.Stop()
+		return ctx.Err()
+	}
+}
+
+// UserServer implements pb.UserServiceServer on top of user.Service.
+type UserServer struct {
+	pb.UnimplementedUserServiceServer
+
+	svc user.Service
+}
+
+func NewUserServer(svc user.Service) *UserServer {
+	return &UserServer{
+		svc: svc,
+	}
+}
+
+func (s UserServer) Create(ctx context.Context, req *pb.CreateRequest) (*pb.User, error) {
+	u := userFromProto(req.GetUser())
+	err := s.svc.Create(ctx, u)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return s.get(ctx, u.ID)
+}
+
+func (s UserServer) Update(ctx context.Context, req *pb.UpdateRequest) (*pb.User, error) {
+	u := userFromProto(req.GetUser())
+	err := s.svc.Update(ctx, u)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return s.get(ctx, u.ID)
+}
+
+func (s UserServer) Delete(ctx context.Context, req *pb.DeleteRequest) (*emptypb.Empty, error) {
+	err := s.svc.Delete(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s UserServer) Get(ctx context.Context, req *pb.GetRequest) (*pb.User, error) {
+	return s.get(ctx, req.GetId())
+}
+
+func (s UserServer) get(ctx context.Context, id string) (*pb.User, error) {
+	u, err := s.svc.Get(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return userToProto(u), nil
+}
+
+func (s UserServer) List(ctx context.Context, req *pb.ListRequest) (*pb.ListResponse, error) {
+	opts := user.NewListOptions()
+	opts.Country = req.GetCountry()
+	opts.Sort = req.GetSort()
+	opts.Cursor = req.GetCursor()
+	if req.GetPerPage() > 0 {
+		opts.PerPage = req.GetPerPage()
+	}
+	opts.Page = int(req.GetPage())
+
+	resp, err := s.svc.List(ctx, opts)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	users := make([]*pb.User, len(resp.Users))
+	for i, u := range resp.Users {
+		users[i] = userToProto(u)
+	}
+	return &pb.ListResponse{
+		Total:      resp.Total,
+		PerPage:    resp.PerPage,
+		Users:      users,
+		NextCursor: resp.NextCursor,
+	}, nil
+}
+
+func userFromProto(pu *pb.User) *user.User {
+	return &user.User{
+		ID:        pu.GetId(),
+		FirstName: pu.GetFirstName(),
+		LastName:  pu.GetLastName(),
+		Nickname:  pu.GetNickname(),
+		Password:  pu.GetPassword(),
+		Email:     pu.GetEmail(),
+		Country:   pu.GetCountry(),
+	}
+}
+
+func userToProto(u *user.User) *pb.User {
+	pu := &pb.User{
+		Id:        u.ID,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Nickname:  u.Nickname,
+		Email:     u.Email,
+		Country:   u.Country,
+		CreatedAt: timestamppb.New(u.CreatedAt),
+	}
+	if u.UpdatedAt != nil {
+		pu.UpdatedAt = timestamppb.New(*u.UpdatedAt)
+	}
+	if u.RemovedAt != nil {
+		pu.RemovedAt = timestamppb.New(*u.RemovedAt)
+	}
+	return pu
+}
+
+// Logger returns a unary interceptor which logs every call, like http.Logger
+// does. It also stores the request id and actor from the metadata in the
+// context, together with a logger carrying the request id. The actor is
+// replaced by the principal added by Authenticate, which is the only one
+// logged.
+func Logger(log logrus.FieldLogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		reqID := firstMetadata(md, RequestIDMetadata)
+		if reqID == "" {
+			reqID = xid.New().String()
+		}
+		ctx = user.SetRequestID(ctx, reqID)
+		if actor := firstMetadata(md, ActorMetadata); actor != "" {
+			ctx = user.SetActor(ctx, actor)
+		}
+		fields := logrus.Fields{
+			"request_id": reqID,
+			"route":      info.FullMethod,
+		}
+		if log != nil {
+			ctx = user.SetLogger(ctx, log)
+		}
+		ctx = user.WithLogFields(ctx, fields)
+		ctx = user.WithPrincipal(ctx)
+
+		t1 := time.Now()
+		resp, err := handler(ctx, req)
+		elapsed := time.Since(t1)
+
+		entry := user.Logger(ctx)
+		if principal := user.Principal(ctx); principal != "" {
+			entry = entry.WithField("principal", principal)
+		}
+		entry.
+			WithFields(logrus.Fields{
+				"code":       status.Code(err).String(),
+				"elapsed_ms": float64(elapsed.Nanoseconds()) / float64(time.Millisecond),
+				"elapsed":    elapsed.String(),
+			}).
+			Info(info.FullMethod)
+		return resp, err
+	}
+}
+
+// Tenant is a unary interceptor which stores in the context the tenant from
+// the metadata, like http.Tenant does.
+func Tenant(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	tenant := firstMetadata(md, TenantMetadata)
+	if tenant == "" {
+		tenant = user.DefaultTenant
+	}
+	err := user.ValidateTenant(tenant)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return handler(withTenant(ctx, tenant), req)
+}
+
+// withTenant stores tenant in ctx and adds it to the logger.
+func withTenant(ctx context.Context, tenant string) context.Context {
+	ctx = user.SetTenant(ctx, tenant)
+	return user.WithLogFields(ctx, logrus.Fields{"tenant": tenant})
+}
+
+func firstMetadata(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	ugrpc "github.com/guilherme-santos/user/grpc"
+	"github.com/guilherme-santos/user/grpc/pb"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestUserServerCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	u := newUser()
+
+	// Creates the user
+	svc := mock.NewUserService(ctrl)
+	svc.EXPECT().
+		Create(gomock.Any(), u).
+		DoAndReturn(func(_ context.Context, u *user.User) error {
+			u.ID = "uuid"
+			return nil
+		})
+	svc.EXPECT().Get(gomock.Any(), "uuid").Return(&user.User{ID: "uuid", Email: u.Email}, nil)
+
+	client := newClient(t, svc)
+	pu, err := client.Create(context.Background(), &pb.CreateRequest{
+		User: &pb.User{
+			FirstName: u.FirstName,
+			LastName:  u.LastName,
+			Password:  u.Password,
+			Email:     u.Email,
+			Country:   u.Country,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "uuid", pu.GetId())
+	assert.Equal(t, u.Email, pu.GetEmail())
+	assert.Empty(t, pu.GetPassword())
+}
+
+func TestUserServerErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := mock.NewUserService(ctrl)
+	svc.EXPECT().Get(gomock.Any(), "uuid").Return(nil, user.ErrNotFound)
+	svc.EXPECT().Update(gomock.Any(), gomock.Any()).Return(user.NewMissingFieldError("email"))
+
+	client := newClient(t, svc)
+
+	_, err := client.Get(context.Background(), &pb.GetRequest{Id: "uuid"})
+	st := status.Convert(err)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "user not found", st.Message())
+
+	_, err = client.Update(context.Background(), &pb.UpdateRequest{User: &pb.User{Id: "uuid"}})
+	st = status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	if details := st.Details(); assert.Len(t, details, 2) {
+		info := details[0].(*errdetails.ErrorInfo)
+		assert.Equal(t, "missing_field", info.GetReason())
+		br := details[1].(*errdetails.BadRequest)
+		assert.Equal(t, "email", br.GetFieldViolations()[0].GetField())
+	}
+}
+
+func TestUserServerActorMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := mock.NewUserService(ctrl)
+	svc.EXPECT().
+		Get(gomock.Any(), "uuid").
+		DoAndReturn(func(ctx context.Context, id string) (*user.User, error) {
+			assert.Equal(t, "admin", user.Actor(ctx))
+			return &user.User{ID: id}, nil
+		})
+
+	client := newClient(t, svc)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), ugrpc.ActorMetadata, "admin")
+	_, err := client.Get(ctx, &pb.GetRequest{Id: "uuid"})
+	require.NoError(t, err)
+}
+
+func newClient(t *testing.T, svc user.Service) pb.UserServiceClient {
+	lis := bufconn.Listen(1024 * 1024)
+	srv := ugrpc.NewServer(nil, svc, nil)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewUserServiceClient(conn)
+}
+
+func newUser() *user.User {
+	return &user.User{
+		FirstName: "Guilherme",
+		LastName:  "S.",
+		Password:  "123456",
+		Email:     "xguiga@gmail.com",
+		Country:   "DE",
+	}
+}
@@ -0,0 +1,166 @@
+// Package health keeps a registry of named checks of the dependencies of the
+// service, e.g. the database, and aggregates their results in a single status.
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status is the aggregate status of the checks.
+type Status string
+
+const (
+	// StatusOK means all checks passed.
+	StatusOK Status = "ok"
+	// StatusDegraded means only non-critical checks failed, the service
+	// still works but some features may not.
+	StatusDegraded Status = "degraded"
+	// StatusDown means a critical check failed.
+	StatusDown Status = "down"
+)
+
+// defaultTimeout is the timeout of checks without one.
+const defaultTimeout = time.Second
+
+// Check is a named check of a dependency.
+type Check struct {
+	Name string
+	// Run returns an error when the dependency isn't healthy.
+	Run func(context.Context) error
+	// Timeout is how long Run has, one second if zero.
+	Timeout time.Duration
+	// Critical checks turn the status down when failing, otherwise
+	// degraded.
+	Critical bool
+}
+
+// Result is the outcome of all checks.
+type Result struct {
+	Status    Status                  `json:"status"`
+	Checks    map[string]*CheckResult `json:"checks,omitempty"`
+	CheckedAt time.Time               `json:"checked_at"`
+}
+
+// CheckResult is the outcome of a check.
+type CheckResult struct {
+	Status    Status  `json:"status"`
+	Critical  bool    `json:"critical"`
+	Error     string  `json:"error,omitempty"`
+	ElapsedMS float64 `json:"elapsed_ms"`
+}
+
+// Registry runs the checks registered, results are cached for ttl so frequent
+// probes don't overload the dependencies.
+type Registry struct {
+	ttl time.Duration
+
+	mu     sync.Mutex
+	checks []Check
+	last   *Result
+}
+
+func NewRegistry(ttl time.Duration) *Registry {
+	return &Registry{
+		ttl: ttl,
+	}
+}
+
+// Register adds c to the checks, replacing the one with the same name.
+func (r *Registry) Register(c Check) {
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.checks {
+		if r.checks[i].Name == c.Name {
+			r.checks[i] = c
+			r.last = nil
+			return
+		}
+	}
+	r.checks = append(r.checks, c)
+	sort.Slice(r.checks, func(i, j int) bool {
+		return r.checks[i].Name < r.checks[j].Name
+	})
+	r.last = nil
+}
+
+// Check returns the cached result, running all checks concurrently if it's
+// older than ttl. Concurrent callers wait for the same run.
+func (r *Registry) Check(ctx context.Context) *Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.last != nil && time.Since(r.last.CheckedAt) < r.ttl {
+		return r.last
+	}
+
+	res := &Result{
+		Status:    StatusOK,
+		Checks:    make(map[string]*CheckResult, len(r.checks)),
+		CheckedAt: time.Now(),
+	}
+	results := make([]*CheckResult, len(r.checks))
+
+	// the result is shared by all callers, so it doesn't depend on the
+	// cancellation of this one
+	ctx = context.WithoutCancel(ctx)
+	var wg sync.WaitGroup
+	for i, c := range r.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	for i, c := range r.checks {
+		cres := results[i]
+		res.Checks[c.Name] = cres
+		switch {
+		case cres.Status == StatusOK:
+		case c.Critical:
+			res.Status = StatusDown
+		case res.Status == StatusOK:
+			res.Status = StatusDegraded
+		}
+	}
+	r.last = res
+	return res
+}
+
+// run runs c until its timeout, even if Run doesn't respect ctx.
+func run(ctx context.Context, c Check) *CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	res := &CheckResult{
+		Status:   StatusOK,
+		Critical: c.Critical,
+	}
+	t1 := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.Run(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	res.ElapsedMS = float64(time.Since(t1).Nanoseconds()) / float64(time.Millisecond)
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+	}
+	return res
+}
@@ -0,0 +1,86 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guilherme-santos/user/health"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	var calls int
+	var dbErr, cacheErr error
+	reg := health.NewRegistry(time.Hour)
+	reg.Register(health.Check{
+		Name: "mysql",
+		Run: func(context.Context) error {
+			calls++
+			return dbErr
+		},
+		Critical: true,
+	})
+	reg.Register(health.Check{
+		Name: "cache",
+		Run:  func(context.Context) error { return cacheErr },
+	})
+
+	res := reg.Check(context.Background())
+	assert.Equal(t, health.StatusOK, res.Status)
+	assert.Equal(t, health.StatusOK, res.Checks["mysql"].Status)
+	assert.True(t, res.Checks["mysql"].Critical)
+
+	// results are cached
+	dbErr = errors.New("connection refused")
+	res = reg.Check(context.Background())
+	assert.Equal(t, health.StatusOK, res.Status)
+	assert.Equal(t, 1, calls)
+
+	tests := []struct {
+		name     string
+		dbErr    error
+		cacheErr error
+		expected health.Status
+	}{
+		{name: "non-critical failing", cacheErr: errors.New("timeout"), expected: health.StatusDegraded},
+		{name: "critical failing", dbErr: errors.New("connection refused"), expected: health.StatusDown},
+		{name: "all failing", dbErr: errors.New("connection refused"), cacheErr: errors.New("timeout"), expected: health.StatusDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbErr, cacheErr = tt.dbErr, tt.cacheErr
+			reg := health.NewRegistry(0)
+			reg.Register(health.Check{Name: "mysql", Run: func(context.Context) error { return dbErr }, Critical: true})
+			reg.Register(health.Check{Name: "cache", Run: func(context.Context) error { return cacheErr }})
+
+			res := reg.Check(context.Background())
+			assert.Equal(t, tt.expected, res.Status)
+			if tt.cacheErr != nil {
+				assert.Equal(t, health.StatusDown, res.Checks["cache"].Status)
+				assert.Equal(t, tt.cacheErr.Error(), res.Checks["cache"].Error)
+			}
+		})
+	}
+}
+
+func TestRegistryTimeout(t *testing.T) {
+	reg := health.NewRegistry(0)
+	reg.Register(health.Check{
+		Name: "events",
+		// ignores ctx
+		Run: func(context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		},
+		Timeout: 10 * time.Millisecond,
+	})
+
+	t1 := time.Now()
+	res := reg.Check(context.Background())
+	assert.Less(t, time.Since(t1), 500*time.Millisecond)
+	assert.Equal(t, health.StatusDegraded, res.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), res.Checks["events"].Error)
+}
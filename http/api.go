@@ -0,0 +1,88 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/health"
+	"github.com/guilherme-santos/user/idempotency"
+	"github.com/guilherme-santos/user/keyring"
+	"github.com/guilherme-santos/user/ratelimit"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// UserService is implemented by the user service exposed by the API.
+type UserService interface {
+	user.Service
+	user.ImportService
+	user.ExportService
+	user.AuditService
+}
+
+// APIConfig contains the services and stores used by the routes of the API.
+type APIConfig struct {
+	Users  UserService
+	Roles  user.RoleService
+	Tokens user.TokenService
+	// APIKeys authenticates the requests, authentication is disabled when nil.
+	APIKeys     user.APIKeyService
+	SigningKeys *keyring.Keyring
+
+	Health *health.Registry
+	// Ready reports whether the server accepts requests, it's false while
+	// shutting down.
+	Ready func() bool
+
+	RateLimitStore   ratelimit.Store
+	RateLimit        RateLimitConfig
+	IdempotencyStore idempotency.Store
+	IdempotencyTTL   time.Duration
+	// Validate validates requests against the OpenAPI spec, nil disables it.
+	Validate func(http.Handler) http.Handler
+}
+
+// NewAPI adds all routes of the service to r: probes, OpenAPI spec, metrics,
+// public keys and the /v1 API.
+func NewAPI(r chi.Router, cfg APIConfig) {
+	// Add liveness and readiness probes, readiness fails while shutting down
+	NewHealthHandler(r, cfg.Health, cfg.Ready)
+	// Add the OpenAPI spec
+	NewOpenAPIHandler(r)
+	// Add prometheus metrics
+	NewMetricsHandler(r)
+	// Add the public keys of the access tokens
+	NewJWKSHandler(r, cfg.SigningKeys)
+	// Add the user handler, clients are limited by ip before the
+	// authentication and by api key after it
+	limitByKey := RateLimitAPIKey(r, cfg.RateLimitStore, cfg.RateLimit)
+	r.Route("/v1", func(v1 chi.Router) {
+		v1.Use(Tenant)
+		v1.Use(RateLimit(r, cfg.RateLimitStore, cfg.RateLimit))
+		if cfg.Validate != nil {
+			v1.Use(cfg.Validate)
+		}
+		// token is public, it's how users authenticate
+		NewTokenHandler(v1, cfg.Tokens)
+		// self-service routes are authenticated by access tokens
+		v1.Group(func(r chi.Router) {
+			r.Use(BearerAuth(cfg.Tokens))
+			NewMeHandler(r, cfg.Users)
+		})
+		// user routes are authenticated by API keys or access tokens
+		v1.Group(func(r chi.Router) {
+			r.Use(AuthenticateAny(cfg.APIKeys, cfg.Tokens))
+			r.Use(limitByKey)
+			// retries of user creation are replayed by Idempotency-Key
+			r.Group(func(r chi.Router) {
+				r.Use(Idempotency(cfg.IdempotencyStore, cfg.IdempotencyTTL))
+				NewUserHandler(r, cfg.Users)
+			})
+			NewImportHandler(r, cfg.Users)
+			NewExportHandler(r, cfg.Users)
+			NewAuditHandler(r, cfg.Users)
+			NewRoleHandler(r, cfg.Roles)
+		})
+	})
+}
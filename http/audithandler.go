@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/guilherme-santos/user"
+)
+
+type AuditHandler struct {
+	svc user.AuditService
+}
+
+func NewAuditHandler(r chi.Router, svc user.AuditService) *AuditHandler {
+	h := &AuditHandler{
+		svc: svc,
+	}
+	r.With(RequireScope(user.ScopeUsersRead)).Get("/users/{id}/audit", h.List)
+	return h
+}
+
+func (h AuditHandler) List(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	opts := user.NewAuditListOptions()
+	if query.Has("per_page") {
+		perPage, err := strconv.ParseInt(query.Get("per_page"), 10, 64)
+		if err == nil {
+			opts.PerPage = perPage
+		}
+	}
+	if query.Has("page") {
+		page, err := strconv.Atoi(query.Get("page"))
+		if err == nil {
+			opts.Page = page
+		}
+	}
+
+	id := chi.URLParam(req, "id")
+	resp, err := h.svc.AuditLog(req.Context(), id, opts)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	respondOK(w, resp)
+}
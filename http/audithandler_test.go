@@ -0,0 +1,54 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	uhttp "github.com/guilherme-santos/user/http"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditHandlerList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/users/uuid/audit?per_page=5&page=1", nil)
+
+	resp := &user.AuditListResponse{
+		Total:   6,
+		PerPage: 5,
+		Entries: []*user.AuditEntry{
+			{
+				ID:     "audit-1",
+				UserID: "uuid",
+				Action: user.AuditUpdated,
+				Actor:  "admin",
+				Changes: map[string]user.AuditChange{
+					"email": {Before: "old@gmail.com", After: "xguiga@gmail.com"},
+				},
+			},
+		},
+	}
+
+	// Retrieves the audit log
+	svc := mock.NewAuditService(ctrl)
+	svc.EXPECT().
+		AuditLog(gomock.Any(), "uuid", &user.AuditListOptions{PerPage: 5, Page: 1}).
+		Return(resp, nil)
+
+	r := uhttp.NewRouter(nil, nil)
+	uhttp.NewAuditHandler(r, svc)
+	r.ServeHTTP(w, req)
+
+	respJSON, _ := json.Marshal(resp)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, string(respJSON), w.Body.String())
+}
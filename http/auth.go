@@ -0,0 +1,118 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/guilherme-santos/user"
+)
+
+// Authenticate returns a middleware which rejects requests without a valid
+// API key in the X-API-Key header. The key is stored in the context and its
+// name becomes the actor of the request, keys bound to a tenant also replace
+// the tenant of the request.
+func Authenticate(svc user.APIKeyService) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			secret := r.Header.Get(APIKeyHeader)
+			if secret == "" {
+				respondWithError(w, user.ErrUnauthenticated)
+				return
+			}
+			k, err := svc.Authenticate(ctx, secret)
+			if err != nil {
+				respondWithError(w, err)
+				return
+			}
+
+			if k.TenantID != "" {
+				ctx = withTenant(ctx, k.TenantID)
+			}
+			ctx = user.SetAPIKey(ctx, k)
+			ctx = user.SetPrincipal(ctx, "apikey:"+k.Name)
+			h.ServeHTTP(w, r.WithContext(ctx))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// AuthenticateAny returns a middleware which authenticates the request with
+// BearerAuth when it has an Authorization header, with Authenticate otherwise.
+// Requests without an access token aren't authenticated when keys is nil, as
+// the authentication of API keys is disabled.
+func AuthenticateAny(keys user.APIKeyService, tokens user.TokenService) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		bearer := BearerAuth(tokens)(h)
+		apikey := h
+		if keys != nil {
+			apikey = Authenticate(keys)(h)
+		}
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "" {
+				bearer.ServeHTTP(w, r)
+				return
+			}
+			apikey.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// RequireScope returns a middleware which rejects requests whose API key
+// doesn't have any of scopes. Requests without a key in the context are
+// allowed, as the authentication is disabled when Authenticate isn't in use.
+func RequireScope(scopes ...string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			k := user.APIKeyFrom(r.Context())
+			if k != nil && !hasAnyScope(k, scopes) {
+				respondWithError(w, user.ErrPermissionDenied)
+				return
+			}
+			h.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// BearerAuth returns a middleware which rejects requests without a valid
+// access token in the Authorization header. The claims are stored in the
+// context and the user becomes the actor of the request, in the tenant of the
+// user.
+func BearerAuth(svc user.TokenService) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+			if !strings.EqualFold(scheme, "Bearer") || token == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				respondWithError(w, user.ErrInvalidToken)
+				return
+			}
+			claims, err := svc.VerifyAccessToken(ctx, token)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				respondWithError(w, err)
+				return
+			}
+
+			if claims.TenantID != "" {
+				ctx = withTenant(ctx, claims.TenantID)
+			}
+			ctx = user.SetTokenClaims(ctx, claims)
+			ctx = user.SetPrincipal(ctx, "user:"+claims.Subject)
+			h.ServeHTTP(w, r.WithContext(ctx))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func hasAnyScope(k *user.APIKey, scopes []string) bool {
+	for _, s := range scopes {
+		if k.HasScope(s) {
+			return true
+		}
+	}
+	return false
+}
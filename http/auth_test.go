@@ -0,0 +1,156 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	uhttp "github.com/guilherme-santos/user/http"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		key    *user.APIKey
+		err    error
+		method string
+		status int
+	}{
+		{
+			name:   "missing key",
+			method: http.MethodGet,
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "invalid key",
+			secret: "usk_invalid",
+			err:    user.ErrUnauthenticated,
+			method: http.MethodGet,
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "missing scope",
+			secret: "usk_valid",
+			key:    &user.APIKey{Name: "reader", Scopes: []string{user.ScopeUsersRead}},
+			method: http.MethodDelete,
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "allowed",
+			secret: "usk_valid",
+			key:    &user.APIKey{Name: "reader", Scopes: []string{user.ScopeUsersRead}},
+			method: http.MethodGet,
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			keysvc := mock.NewAPIKeyService(ctrl)
+			if tt.secret != "" {
+				keysvc.EXPECT().Authenticate(gomock.Any(), tt.secret).Return(tt.key, tt.err)
+			}
+			svc := mock.NewUserService(ctrl)
+			if tt.status == http.StatusOK {
+				svc.EXPECT().
+					Get(gomock.Any(), "uuid").
+					DoAndReturn(func(ctx context.Context, id string) (*user.User, error) {
+						// the key name is the actor of the request
+						assert.Equal(t, "apikey:reader", user.Actor(ctx))
+						return &user.User{ID: id}, nil
+					})
+			}
+
+			r := uhttp.NewRouter(nil, nil)
+			r.Use(uhttp.Authenticate(keysvc))
+			uhttp.NewUserHandler(r, svc)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, "/users/uuid", nil)
+			if tt.secret != "" {
+				req.Header.Set(uhttp.APIKeyHeader, tt.secret)
+			}
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestBearerAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		claims        *user.TokenClaims
+		err           error
+		status        int
+	}{
+		{
+			name:   "missing token",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:          "other scheme",
+			authorization: "Basic am9objpkb2U=",
+			status:        http.StatusUnauthorized,
+		},
+		{
+			name:          "invalid token",
+			authorization: "Bearer invalid",
+			err:           user.ErrInvalidToken,
+			status:        http.StatusUnauthorized,
+		},
+		{
+			name:          "valid token",
+			authorization: "Bearer valid",
+			claims:        &user.TokenClaims{Subject: "user-1"},
+			status:        http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tokensvc := mock.NewTokenService(ctrl)
+			if tt.claims != nil || tt.err != nil {
+				tokensvc.EXPECT().VerifyAccessToken(gomock.Any(), gomock.Any()).Return(tt.claims, tt.err)
+			}
+			svc := mock.NewUserService(ctrl)
+			if tt.status == http.StatusOK {
+				svc.EXPECT().
+					Get(gomock.Any(), "user-1").
+					DoAndReturn(func(ctx context.Context, id string) (*user.User, error) {
+						// the user is the actor of the request
+						assert.Equal(t, "user:user-1", user.Actor(ctx))
+						return &user.User{ID: id}, nil
+					})
+			}
+
+			r := uhttp.NewRouter(nil, nil)
+			r.Use(uhttp.BearerAuth(tokensvc))
+			uhttp.NewMeHandler(r, svc)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/me", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusUnauthorized {
+				assert.Contains(t, w.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+		})
+	}
+}
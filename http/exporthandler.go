@@ -0,0 +1,86 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/guilherme-santos/user"
+)
+
+var CSVContentType = "text/csv; charset=UTF-8"
+
+type ExportHandler struct {
+	svc user.ExportService
+}
+
+func NewExportHandler(r chi.Router, svc user.ExportService) *ExportHandler {
+	h := &ExportHandler{
+		svc: svc,
+	}
+	r.With(RequireScope(user.ScopeUsersRead, user.ScopeUsersReadPublic)).Get("/users:export", h.Export)
+	return h
+}
+
+// Export streams all users matching the same filters accepted by List, as
+// NDJSON or CSV.
+func (h ExportHandler) Export(w http.ResponseWriter, req *http.Request) {
+	stream(w, req)
+	query := req.URL.Query()
+	opts := user.NewListOptions()
+	opts.Country = query.Get("country")
+	opts.Sort = query.Get("sort")
+
+	var fields []string
+	if query.Get("fields") != "" {
+		fields = strings.Split(query.Get("fields"), ",")
+	}
+
+	fields, err := projectFields(projection(req.Context()), fields)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	format := query.Get("format")
+	enc, err := user.NewExportEncoder(w, format, fields)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Headers are only sent with the first user, so errors before that can
+	// still be reported properly.
+	var started bool
+	start := func() {
+		if format == "csv" {
+			w.Header().Set("Content-Type", CSVContentType)
+			w.Header().Set("Content-Disposition", `attachment; filename="users.csv"`)
+		} else {
+			w.Header().Set("Content-Type", NDJSONContentType)
+		}
+		w.WriteHeader(http.StatusOK)
+		started = true
+	}
+
+	ctx := req.Context()
+	err = h.svc.Export(ctx, opts, func(u *user.User) error {
+		if !started {
+			start()
+		}
+		return enc.Encode(u)
+	})
+	if err != nil {
+		if !started {
+			respondWithError(w, err)
+			return
+		}
+		// it's too late to report the error to the client
+		user.Logger(ctx).WithError(err).Error("unable to export users")
+		return
+	}
+	if !started {
+		start()
+	}
+	enc.Flush()
+}
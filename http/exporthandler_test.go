@@ -0,0 +1,67 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	uhttp "github.com/guilherme-santos/user/http"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	u1 := newUser()
+	u1.ID = "uuid-1"
+	u2 := newUser()
+	u2.ID = "uuid-2"
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/users:export?format=csv&fields=id,email&country=DE&sort=-id", nil)
+
+	// Exports the users
+	svc := mock.NewExportService(ctrl)
+	svc.EXPECT().
+		Export(gomock.Any(), &user.ListOptions{Country: "DE", Sort: "-id", PerPage: 10}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *user.ListOptions, fn func(*user.User) error) error {
+			assert.NoError(t, fn(u1))
+			assert.NoError(t, fn(u2))
+			return nil
+		})
+
+	r := uhttp.NewRouter(nil, nil)
+	uhttp.NewExportHandler(r, svc)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, uhttp.CSVContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, "id,email\nuuid-1,xguiga@gmail.com\nuuid-2,xguiga@gmail.com\n", w.Body.String())
+}
+
+func TestExportHandlerError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/users:export", nil)
+
+	// Fails before any user is exported
+	svc := mock.NewExportService(ctrl)
+	svc.EXPECT().
+		Export(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(user.ErrExportNotSupported)
+
+	r := uhttp.NewRouter(nil, nil)
+	uhttp.NewExportHandler(r, svc)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"code":"export_not_supported","message":"storage doesn't support export"}`, w.Body.String())
+}
@@ -1,42 +1,53 @@
 package http
 
 import (
-	"database/sql"
 	"net/http"
 
+	"github.com/guilherme-santos/user/health"
+
 	"github.com/go-chi/chi/v5"
 )
 
 type HealthHandler struct {
-	db *sql.DB
+	checks *health.Registry
+	ready  func() bool
 }
 
-func NewHealthHandler(r chi.Router, db *sql.DB) *HealthHandler {
+// NewHealthHandler adds the liveness and readiness probes, readiness runs the
+// checks and fails while ready returns false, e.g. when shutting down.
+func NewHealthHandler(r chi.Router, checks *health.Registry, ready func() bool) *HealthHandler {
 	h := &HealthHandler{
-		db: db,
+		checks: checks,
+		ready:  ready,
 	}
-	r.Get("/health", h.Health)
+	r.Get("/health", h.Ready)
+	r.Get("/health/live", h.Live)
+	r.Get("/health/ready", h.Ready)
 	return h
 }
 
-func (h HealthHandler) Health(w http.ResponseWriter, req *http.Request) {
-	res := map[string]interface{}{
-		"status": "ok",
-	}
-	dbres := map[string]interface{}{}
-
-	err := h.db.PingContext(req.Context())
-	if err != nil {
-		dbres["status"] = "error"
-		dbres["error"] = err.Error()
-	} else {
-		dbres["status"] = "ok"
-		stats := h.db.Stats()
-		dbres["open_conns"] = stats.OpenConnections
-		dbres["in_use_conns"] = stats.InUse
-		dbres["idle_conns"] = stats.Idle
+// Live reports the process is running, it doesn't depend on anything else so
+// failing dependencies don't restart the service.
+func (h HealthHandler) Live(w http.ResponseWriter, req *http.Request) {
+	respondOK(w, map[string]interface{}{
+		"status": health.StatusOK,
+	})
+}
+
+// Ready reports whether the instance accepts new requests, it returns 503
+// when a critical check fails and 200 when it's degraded.
+func (h HealthHandler) Ready(w http.ResponseWriter, req *http.Request) {
+	if h.ready != nil && !h.ready() {
+		respond(w, http.StatusServiceUnavailable, map[string]interface{}{
+			"status": "draining",
+		})
+		return
 	}
 
-	res["mysql"] = dbres
-	respondOK(w, res)
+	res := h.checks.Check(req.Context())
+	status := http.StatusOK
+	if res.Status == health.StatusDown {
+		status = http.StatusServiceUnavailable
+	}
+	respond(w, status, res)
 }
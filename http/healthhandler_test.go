@@ -0,0 +1,54 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/guilherme-santos/user/health"
+	uhttp "github.com/guilherme-santos/user/http"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthHandler(t *testing.T) {
+	var dbErr error
+	ready := true
+	checks := health.NewRegistry(0)
+	checks.Register(health.Check{
+		Name:     "mysql",
+		Run:      func(context.Context) error { return dbErr },
+		Critical: true,
+	})
+
+	r := uhttp.NewRouter(nil, nil)
+	uhttp.NewHealthHandler(r, checks, func() bool { return ready })
+
+	do := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("/health/ready")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"ok"`)
+
+	dbErr = errors.New("connection refused")
+	w = do("/health/ready")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), `"error":"connection refused"`)
+
+	// liveness doesn't depend on the checks
+	w = do("/health/live")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	dbErr = nil
+	ready = false
+	w = do("/health/ready")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"status":"draining"}`, w.Body.String())
+}
@@ -0,0 +1,156 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/idempotency"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+const (
+	// IdempotencyKeyHeader identifies retries of the same request.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set in responses replayed from a previous
+	// request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLen is the max length of an idempotency key.
+	maxIdempotencyKeyLen = 255
+)
+
+var (
+	ErrInvalidIdempotencyKey = &user.Error{
+		Type:    user.InvalidArgument,
+		Code:    "invalid_idempotency_key",
+		Message: "Idempotency-Key must have up to 255 characters",
+	}
+	ErrIdempotencyKeyInUse = &user.Error{
+		Type:    user.Conflict,
+		Code:    "idempotency_key_in_use",
+		Message: "A request with the same Idempotency-Key is in progress, retry later",
+	}
+	ErrIdempotencyKeyReused = &user.Error{
+		Type:    user.Unprocessable,
+		Code:    "idempotency_key_reused",
+		Message: "Idempotency-Key was already used with a different payload",
+	}
+)
+
+// Idempotency returns a middleware which keeps the response of POST requests
+// with an Idempotency-Key header for ttl, retries with the same key and
+// payload receive the same response instead of running the request again.
+// Keys are scoped by tenant and actor, so clients can't see responses of
+// each other. Server errors and panics aren't kept, so the request can be
+// retried. Failures of store are logged and the request runs as if it had no
+// key.
+func Idempotency(store idempotency.Store, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			idemKey := r.Header.Get(IdempotencyKeyHeader)
+			if r.Method != http.MethodPost || idemKey == "" {
+				h.ServeHTTP(w, r)
+				return
+			}
+			if len(idemKey) > maxIdempotencyKeyLen {
+				respondWithError(w, ErrInvalidIdempotencyKey)
+				return
+			}
+
+			ctx := r.Context()
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				if isBodyTooLarge(err) {
+					err = ErrBodyTooLarge
+				}
+				respondWithError(w, err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			key := idempotencyStoreKey(ctx, idemKey)
+			fingerprint := idempotencyFingerprint(r, body)
+			rec, err := store.Reserve(ctx, key, fingerprint, ttl)
+			if err != nil {
+				user.Logger(ctx).WithError(err).Error("unable to reserve idempotency key")
+				h.ServeHTTP(w, r)
+				return
+			}
+			if rec != nil {
+				switch {
+				case rec.Fingerprint != fingerprint:
+					respondWithError(w, ErrIdempotencyKeyReused)
+				case rec.Response == nil:
+					respondWithError(w, ErrIdempotencyKeyInUse)
+				default:
+					replay(w, rec.Response)
+				}
+				return
+			}
+
+			// the response is kept even if the client gave up waiting for it
+			storeCtx := context.WithoutCancel(ctx)
+			defer func() {
+				if p := recover(); p != nil {
+					// otherwise retries would be rejected until ttl expires
+					err := store.Release(storeCtx, key)
+					if err != nil {
+						user.Logger(ctx).WithError(err).Error("unable to release idempotency key")
+					}
+					panic(p)
+				}
+			}()
+
+			var buf bytes.Buffer
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&buf)
+			h.ServeHTTP(ww, r)
+
+			ctx = storeCtx
+			status := ww.Status()
+			if status >= http.StatusInternalServerError {
+				err = store.Release(ctx, key)
+			} else {
+				err = store.Complete(ctx, key, &idempotency.Response{
+					Status:      status,
+					ContentType: ww.Header().Get("Content-Type"),
+					Body:        buf.Bytes(),
+				}, ttl)
+			}
+			if err != nil {
+				user.Logger(ctx).WithError(err).Error("unable to store idempotent response")
+			}
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func replay(w http.ResponseWriter, resp *idempotency.Response) {
+	if resp.ContentType != "" {
+		w.Header().Set("Content-Type", resp.ContentType)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body)
+}
+
+// idempotencyStoreKey returns the key of the record in the store, it's hashed
+// so the length is fixed.
+func idempotencyStoreKey(ctx context.Context, idemKey string) string {
+	sum := sha256.Sum256([]byte(user.Tenant(ctx) + "\x00" + user.Actor(ctx) + "\x00" + idemKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// idempotencyFingerprint identifies the payload of the request.
+func idempotencyFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.Path+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,95 @@
+package http_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guilherme-santos/user"
+	uhttp "github.com/guilherme-santos/user/http"
+	"github.com/guilherme-santos/user/idempotency"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency(t *testing.T) {
+	var calls int
+	r := uhttp.NewRouter(nil, nil)
+	r.Use(uhttp.Idempotency(idempotency.NewMemoryStore(), time.Hour))
+	r.Post("/users", func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		body, _ := io.ReadAll(req.Body)
+		switch string(body) {
+		case "fail":
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		case "panic":
+			panic("unexpected")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"1","body":"` + string(body) + `"}`))
+	})
+
+	do := func(key, actor, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(uhttp.IdempotencyKeyHeader, key)
+		}
+		// the actor is set by the authentication
+		req = req.WithContext(user.SetActor(req.Context(), actor))
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("k1", "alice", "a")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get(uhttp.IdempotentReplayedHeader))
+	assert.Equal(t, 1, calls)
+
+	// retry is replayed
+	w = do("k1", "alice", "a")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "true", w.Header().Get(uhttp.IdempotentReplayedHeader))
+	assert.JSONEq(t, `{"id":"1","body":"a"}`, w.Body.String())
+	assert.Equal(t, 1, calls)
+
+	// same key with another payload
+	w = do("k1", "alice", "b")
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{"code":"idempotency_key_reused","message":"Idempotency-Key was already used with a different payload"}`, w.Body.String())
+	assert.Equal(t, 1, calls)
+
+	// keys are scoped by actor
+	w = do("k1", "bob", "b")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 2, calls)
+
+	// requests without key always run
+	do("", "alice", "a")
+	do("", "alice", "a")
+	assert.Equal(t, 4, calls)
+
+	// server errors aren't kept
+	w = do("k2", "alice", "fail")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	w = do("k2", "alice", "fail")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, w.Header().Get(uhttp.IdempotentReplayedHeader))
+	assert.Equal(t, 6, calls)
+
+	// keys of requests which panicked are released too
+	w = do("k3", "alice", "panic")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	w = do("k3", "alice", "panic")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, 8, calls)
+
+	w = do(strings.Repeat("k", 256), "alice", "a")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,221 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/guilherme-santos/user"
+)
+
+type ImportHandler struct {
+	svc user.ImportService
+}
+
+func NewImportHandler(r chi.Router, svc user.ImportService) *ImportHandler {
+	h := &ImportHandler{
+		svc: svc,
+	}
+	r.With(RequireScope(user.ScopeUsersWrite)).Post("/users:import", h.Import)
+	return h
+}
+
+// Import creates users from a NDJSON or CSV body, the outcome of each row is
+// streamed back as NDJSON followed by a summary of the import.
+func (h ImportHandler) Import(w http.ResponseWriter, req *http.Request) {
+	stream(w, req)
+	query := req.URL.Query()
+	opts := user.NewImportOptions()
+	opts.DryRun = query.Get("dry_run") == "true"
+	opts.PreHashed = query.Get("pre_hashed") == "true"
+
+	switch mode := query.Get("mode"); mode {
+	case "", "partial":
+	case "all_or_nothing":
+		opts.AllOrNothing = true
+	default:
+		respondWithError(w, &user.Error{
+			Type:    user.InvalidArgument,
+			Code:    "invalid_mode",
+			Message: "Mode should be partial or all_or_nothing",
+		})
+		return
+	}
+	if query.Has("batch_size") {
+		batchSize, err := strconv.Atoi(query.Get("batch_size"))
+		if err == nil {
+			opts.BatchSize = batchSize
+		}
+	}
+
+	r, err := newImportReader(req)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", NDJSONContentType)
+	w.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	write := func(v interface{}) {
+		enc.Encode(v)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	summary, err := h.svc.Import(req.Context(), r, opts, func(res *user.ImportResult) {
+		row := importRow{
+			Row: res.Row,
+			ID:  res.ID,
+		}
+		if res.Error != nil {
+			_, row.Error = errorResponse(res.Error)
+		}
+		write(row)
+	})
+	if err != nil {
+		// status code was already sent, so the error is the last line.
+		_, body := errorResponse(err)
+		write(map[string]interface{}{"error": body})
+		return
+	}
+	write(map[string]interface{}{"summary": summary})
+}
+
+type importRow struct {
+	Row   int    `json:"row"`
+	ID    string `json:"id,omitempty"`
+	Error error  `json:"error,omitempty"`
+}
+
+// importUser is the format of each row, it allows pre-hashed passwords.
+type importUser struct {
+	user.User
+	PasswordHash string `json:"password_hash"`
+}
+
+func newImportReader(req *http.Request) (user.ImportReader, error) {
+	format := req.URL.Query().Get("format")
+	if format == "" {
+		mediatype, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		switch mediatype {
+		case "application/x-ndjson", "application/json":
+			format = "ndjson"
+		case "text/csv":
+			format = "csv"
+		}
+	}
+
+	switch format {
+	case "ndjson":
+		return &ndjsonImportReader{r: bufio.NewReader(req.Body)}, nil
+	case "csv":
+		return newCSVImportReader(req.Body)
+	}
+	return nil, &user.Error{
+		Type:    user.InvalidArgument,
+		Code:    "invalid_format",
+		Message: "Body should be application/x-ndjson or text/csv",
+	}
+}
+
+// ndjsonImportReader reads one user per line, empty lines are ignored.
+type ndjsonImportReader struct {
+	r *bufio.Reader
+}
+
+func (r *ndjsonImportReader) Read() (*user.User, error) {
+	for {
+		line, err := r.r.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			return nil, err
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var iu importUser
+		err = json.Unmarshal(line, &iu)
+		if err != nil {
+			return nil, newJSONDecodeError(err)
+		}
+		iu.User.PasswordHash = iu.PasswordHash
+		return &iu.User, nil
+	}
+}
+
+// csvImportReader reads one user per record, the first record is the header
+// with the name of the columns.
+type csvImportReader struct {
+	r       *csv.Reader
+	columns []string
+}
+
+var csvImportColumns = map[string]func(*user.User, string){
+	"first_name":    func(u *user.User, v string) { u.FirstName = v },
+	"last_name":     func(u *user.User, v string) { u.LastName = v },
+	"nickname":      func(u *user.User, v string) { u.Nickname = v },
+	"password":      func(u *user.User, v string) { u.Password = v },
+	"password_hash": func(u *user.User, v string) { u.PasswordHash = v },
+	"email":         func(u *user.User, v string) { u.Email = v },
+	"country":       func(u *user.User, v string) { u.Country = v },
+}
+
+func newCSVImportReader(r io.Reader) (*csvImportReader, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = errors.New("header is missing")
+		}
+		return nil, newCSVDecodeError(err)
+	}
+	columns := make([]string, len(header))
+	for i, col := range header {
+		col = strings.ToLower(strings.TrimSpace(col))
+		if _, ok := csvImportColumns[col]; !ok {
+			return nil, newCSVDecodeError(errors.New("unknown column " + strconv.Quote(col)))
+		}
+		columns[i] = col
+	}
+	return &csvImportReader{r: cr, columns: columns}, nil
+}
+
+func (r *csvImportReader) Read() (*user.User, error) {
+	record, err := r.r.Read()
+	if err != nil {
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			return nil, newCSVDecodeError(err)
+		}
+		return nil, err
+	}
+
+	u := new(user.User)
+	for i, v := range record {
+		csvImportColumns[r.columns[i]](u, v)
+	}
+	return u, nil
+}
+
+func newCSVDecodeError(err error) *user.Error {
+	return &user.Error{
+		Type:    user.InvalidArgument,
+		Code:    "invalid_csv",
+		Message: err.Error(),
+	}
+}
@@ -0,0 +1,108 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	uhttp "github.com/guilherme-santos/user/http"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportHandlerNDJSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reqBody := strings.NewReader(`{"first_name":"Guilherme","last_name":"S.","password":"123456","email":"xguiga@gmail.com","country":"DE"}
+
+{"first_name":
+`)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/users:import?dry_run=true&mode=all_or_nothing", reqBody)
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	// Imports the users
+	svc := mock.NewImportService(ctrl)
+	svc.EXPECT().
+		Import(gomock.Any(), gomock.Any(), &user.ImportOptions{DryRun: true, AllOrNothing: true, BatchSize: 100, AllOrNothingLimit: 1000}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, r user.ImportReader, _ *user.ImportOptions, fn func(*user.ImportResult)) (*user.ImportSummary, error) {
+			u, err := r.Read()
+			assert.NoError(t, err)
+			assert.Equal(t, newUser(), u)
+			fn(&user.ImportResult{Row: 1, ID: "uuid"})
+
+			_, err = r.Read()
+			fn(&user.ImportResult{Row: 2, Error: err})
+			return &user.ImportSummary{Total: 2, Created: 1, Failed: 1}, nil
+		})
+
+	r := uhttp.NewRouter(nil, nil)
+	uhttp.NewImportHandler(r, svc)
+	r.ServeHTTP(w, req)
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, uhttp.NDJSONContentType, w.Header().Get("Content-Type"))
+	if assert.Len(t, lines, 3) {
+		assert.JSONEq(t, `{"row":1,"id":"uuid"}`, lines[0])
+		assert.JSONEq(t, `{"row":2,"error":{"code":"invalid_json","message":"unexpected end of JSON input"}}`, lines[1])
+		assert.JSONEq(t, `{"summary":{"total":2,"created":1,"failed":1,"dry_run":false}}`, lines[2])
+	}
+}
+
+func TestImportHandlerCSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reqBody := strings.NewReader("first_name,last_name,password,email,country\nGuilherme,S.,123456,xguiga@gmail.com,DE\nGuilherme,S.\n")
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/users:import", reqBody)
+	req.Header.Set("Content-Type", "text/csv")
+
+	// Imports the users
+	svc := mock.NewImportService(ctrl)
+	svc.EXPECT().
+		Import(gomock.Any(), gomock.Any(), user.NewImportOptions(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, r user.ImportReader, _ *user.ImportOptions, _ func(*user.ImportResult)) (*user.ImportSummary, error) {
+			u, err := r.Read()
+			assert.NoError(t, err)
+			assert.Equal(t, newUser(), u)
+
+			_, err = r.Read()
+			assert.EqualError(t, err, "code=invalid_csv message=record on line 3: wrong number of fields")
+			return &user.ImportSummary{Total: 2}, nil
+		})
+
+	r := uhttp.NewRouter(nil, nil)
+	uhttp.NewImportHandler(r, svc)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestImportHandlerInvalidFormat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/users:import", strings.NewReader("<users/>"))
+	req.Header.Set("Content-Type", "application/xml")
+
+	svc := mock.NewImportService(ctrl)
+
+	r := uhttp.NewRouter(nil, nil)
+	uhttp.NewImportHandler(r, svc)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"code":"invalid_format","message":"Body should be application/x-ndjson or text/csv"}`, w.Body.String())
+}
@@ -0,0 +1,27 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/guilherme-santos/user/keyring"
+)
+
+// JWKSHandler publishes the public keys used to verify the access tokens.
+type JWKSHandler struct {
+	keys *keyring.Keyring
+}
+
+func NewJWKSHandler(r chi.Router, keys *keyring.Keyring) *JWKSHandler {
+	h := &JWKSHandler{
+		keys: keys,
+	}
+	r.Get("/.well-known/jwks.json", h.JWKS)
+	return h
+}
+
+func (h JWKSHandler) JWKS(w http.ResponseWriter, req *http.Request) {
+	// keys are rotated, so clients shouldn't cache them for too long
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	respondOK(w, h.keys.JWKS())
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/guilherme-santos/user"
+)
+
+// MeHandler lets the users authenticated by an access token manage their
+// own account, it must be used after BearerAuth. Access tokens of deleted
+// users are rejected, as they're valid until they expire.
+type MeHandler struct {
+	svc user.Service
+}
+
+func NewMeHandler(r chi.Router, svc user.Service) *MeHandler {
+	h := &MeHandler{
+		svc: svc,
+	}
+	r.Get("/me", h.Get)
+	r.Put("/me", h.Update)
+	return h
+}
+
+func (h MeHandler) Get(w http.ResponseWriter, req *http.Request) {
+	claims := user.TokenClaimsFrom(req.Context())
+	if claims == nil {
+		respondWithError(w, user.ErrInvalidToken)
+		return
+	}
+
+	u, err := h.active(req.Context(), claims.Subject)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	respondOK(w, u)
+}
+
+func (h MeHandler) Update(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	claims := user.TokenClaimsFrom(ctx)
+	if claims == nil {
+		respondWithError(w, user.ErrInvalidToken)
+		return
+	}
+
+	_, err := h.active(ctx, claims.Subject)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// a null body leaves u empty, so it fails the validation
+	u := new(user.User)
+
+	err = json.NewDecoder(req.Body).Decode(u)
+	if err != nil {
+		respondWithError(w, newJSONDecodeError(err))
+		return
+	}
+
+	u.ID = claims.Subject
+
+	err = h.svc.Update(ctx, u)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	u, err = h.svc.Get(ctx, u.ID)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	respondOK(w, u)
+}
+
+// active returns the user with id, ErrInvalidToken is returned when it was
+// deleted.
+func (h MeHandler) active(ctx context.Context, id string) (*user.User, error) {
+	u, err := h.svc.Get(ctx, id)
+	if err == user.ErrNotFound || (err == nil && u.RemovedAt != nil) {
+		return nil, user.ErrInvalidToken
+	}
+	return u, err
+}
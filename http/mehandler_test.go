@@ -0,0 +1,112 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guilherme-santos/user"
+	uhttp "github.com/guilherme-santos/user/http"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// authenticatedAs stores claims of subject in the context, as BearerAuth does.
+func authenticatedAs(subject string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, req *http.Request) {
+			ctx := user.SetTokenClaims(req.Context(), &user.TokenClaims{Subject: subject})
+			h.ServeHTTP(w, req.WithContext(ctx))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func TestMeHandlerRemovedUser(t *testing.T) {
+	removedAt := time.Now()
+
+	tests := []struct {
+		name   string
+		method string
+		user   *user.User
+		err    error
+		status int
+	}{
+		{
+			name:   "get",
+			method: http.MethodGet,
+			user:   &user.User{ID: "user-1"},
+			status: http.StatusOK,
+		},
+		{
+			name:   "get removed",
+			method: http.MethodGet,
+			user:   &user.User{ID: "user-1", RemovedAt: &removedAt},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "get not found",
+			method: http.MethodGet,
+			err:    user.ErrNotFound,
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "update removed",
+			method: http.MethodPut,
+			user:   &user.User{ID: "user-1", RemovedAt: &removedAt},
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// removed users are never updated
+			svc := mock.NewUserService(ctrl)
+			svc.EXPECT().Get(gomock.Any(), "user-1").Return(tt.user, tt.err)
+
+			r := uhttp.NewRouter(nil, nil)
+			r.Use(authenticatedAs("user-1"))
+			uhttp.NewMeHandler(r, svc)
+
+			reqBody := new(bytes.Buffer)
+			json.NewEncoder(reqBody).Encode(newUser())
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, "/me", reqBody)
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusUnauthorized {
+				assert.JSONEq(t, `{"code":"invalid_token","message":"Invalid or expired token"}`, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestMeHandlerUpdateNullBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := mock.NewUserService(ctrl)
+	svc.EXPECT().Get(gomock.Any(), "user-1").Return(&user.User{ID: "user-1"}, nil)
+	svc.EXPECT().
+		Update(gomock.Any(), &user.User{ID: "user-1"}).
+		Return(user.NewMissingFieldError("email"))
+
+	r := uhttp.NewRouter(nil, nil)
+	r.Use(authenticatedAs("user-1"))
+	uhttp.NewMeHandler(r, svc)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/me", strings.NewReader("null"))
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
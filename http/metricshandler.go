@@ -0,0 +1,26 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/guilherme-santos/user/metrics"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type MetricsHandler struct {
+	handler http.Handler
+}
+
+func NewMetricsHandler(r chi.Router) *MetricsHandler {
+	h := &MetricsHandler{
+		handler: metrics.Handler(),
+	}
+	r.Get("/metrics", h.Metrics)
+	return h
+}
+
+// Metrics exposes all prometheus metrics.
+func (h MetricsHandler) Metrics(w http.ResponseWriter, req *http.Request) {
+	h.handler.ServeHTTP(w, req)
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"context"
+	_ "embed"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/guilherme-santos/user"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/go-chi/chi/v5"
+)
+
+// OpenAPISpec is the OpenAPI 3 document of all handlers in this package, it
+// must be updated together with the routes.
+//
+//go:embed openapi.json
+var OpenAPISpec []byte
+
+type OpenAPIHandler struct{}
+
+func NewOpenAPIHandler(r chi.Router) *OpenAPIHandler {
+	h := &OpenAPIHandler{}
+	r.Get("/openapi.json", h.Spec)
+	return h
+}
+
+func (h OpenAPIHandler) Spec(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", JSONContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(OpenAPISpec)
+}
+
+// LoadOpenAPI parses and validates OpenAPISpec.
+func LoadOpenAPI() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(OpenAPISpec)
+	if err != nil {
+		return nil, err
+	}
+	err = doc.Validate(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// ValidateRequests returns a middleware which validates every request against
+// OpenAPISpec, violations are reported as invalid_request errors. Only JSON
+// bodies are validated, so streamed bodies (e.g. import) are not buffered.
+// Requests to routes missing in the spec are passed through.
+func ValidateRequests() (func(http.Handler) http.Handler, error) {
+	doc, err := LoadOpenAPI()
+	if err != nil {
+		return nil, err
+	}
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(h http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, req *http.Request) {
+			route, params, err := router.FindRoute(req)
+			if err != nil {
+				// let the router reply with not found or method not allowed
+				h.ServeHTTP(w, req)
+				return
+			}
+
+			mediatype, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+			input := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: params,
+				Route:      route,
+				Options: &openapi3filter.Options{
+					ExcludeRequestBody: req.ContentLength != 0 && mediatype != "application/json",
+					MultiError:         false,
+					// API keys are checked by Authenticate
+					AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				},
+			}
+			err = openapi3filter.ValidateRequest(req.Context(), input)
+			if err != nil {
+				respondWithError(w, newInvalidRequestError(err))
+				return
+			}
+			h.ServeHTTP(w, req)
+		}
+		return http.HandlerFunc(fn)
+	}, nil
+}
+
+func newInvalidRequestError(err error) *user.Error {
+	msg := err.Error()
+	// kin-openapi appends the whole schema to the message
+	if i := strings.Index(msg, "\nSchema:"); i > 0 {
+		msg = msg[:i]
+	}
+	return &user.Error{
+		Type:    user.InvalidArgument,
+		Code:    "invalid_request",
+		Message: msg,
+	}
+}
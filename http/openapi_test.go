@@ -0,0 +1,138 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	uhttp "github.com/guilherme-santos/user/http"
+	"github.com/guilherme-santos/user/idempotency"
+	"github.com/guilherme-santos/user/mock"
+	"github.com/guilherme-santos/user/ratelimit"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// userService combines the mocks of all services exposed by the API.
+type userService struct {
+	*mock.UserService
+	*mock.ImportService
+	*mock.ExportService
+	*mock.AuditService
+}
+
+// TestOpenAPIRoutes fails when a route is added or removed without updating
+// the OpenAPI spec (or the other way around), the routes are the ones served
+// by the binary.
+func TestOpenAPIRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	doc, err := uhttp.LoadOpenAPI()
+	require.NoError(t, err)
+
+	var specRoutes []string
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			specRoutes = append(specRoutes, method+" "+path)
+		}
+	}
+
+	r := uhttp.NewRouter(nil, nil)
+	uhttp.NewAPI(r, uhttp.APIConfig{
+		Users: userService{
+			UserService:   mock.NewUserService(ctrl),
+			ImportService: mock.NewImportService(ctrl),
+			ExportService: mock.NewExportService(ctrl),
+			AuditService:  mock.NewAuditService(ctrl),
+		},
+		Roles:            mock.NewRoleService(ctrl),
+		Tokens:           mock.NewTokenService(ctrl),
+		RateLimitStore:   ratelimit.NewMemoryStore(),
+		IdempotencyStore: idempotency.NewMemoryStore(),
+	})
+
+	var routes []string
+	chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routes = append(routes, method+" "+strings.TrimSuffix(route, "/"))
+		return nil
+	})
+
+	sort.Strings(specRoutes)
+	sort.Strings(routes)
+	assert.Equal(t, specRoutes, routes)
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+
+	r := uhttp.NewRouter(nil, nil)
+	uhttp.NewOpenAPIHandler(r)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, string(uhttp.OpenAPISpec), w.Body.String())
+}
+
+func TestValidateRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validate, err := uhttp.ValidateRequests()
+	require.NoError(t, err)
+
+	// No call reaches the service
+	svc := mock.NewUserService(ctrl)
+
+	r := uhttp.NewRouter(nil, nil)
+	r.Route("/v1", func(r chi.Router) {
+		r.Use(validate)
+		uhttp.NewUserHandler(r, svc)
+	})
+
+	testcases := []struct {
+		Name    string
+		Method  string
+		URL     string
+		Body    string
+		Message string
+	}{
+		{
+			Name:    "invalid query",
+			Method:  http.MethodGet,
+			URL:     "/v1/users?per_page=abc",
+			Message: `parameter "per_page" in query has an error: value abc: an invalid integer: invalid syntax`,
+		},
+		{
+			Name:    "invalid body",
+			Method:  http.MethodPost,
+			URL:     "/v1/users",
+			Body:    `{"first_name":1}`,
+			Message: `request body has an error: doesn't match schema #/components/schemas/UserInput: Error at "/first_name": value must be a string`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.Method, tc.URL, strings.NewReader(tc.Body))
+			if tc.Body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			r.ServeHTTP(w, req)
+
+			var body map[string]string
+			json.NewDecoder(w.Body).Decode(&body)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, map[string]string{"code": "invalid_request", "message": tc.Message}, body)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/guilherme-santos/user"
+)
+
+// projection returns the fields of users the caller can see. API keys with
+// only the users:read_public scope see user.PublicProjection, while any other
+// caller, including users authenticated by access tokens, see all fields.
+func projection(ctx context.Context) user.Projection {
+	k := user.APIKeyFrom(ctx)
+	if k == nil || k.HasScope(user.ScopeUsersRead) {
+		return nil
+	}
+	return user.PublicProjection
+}
+
+// projectUser returns what is serialized of u, fields out of p are omitted.
+func projectUser(p user.Projection, u *user.User) interface{} {
+	if p == nil {
+		return u
+	}
+	b, err := json.Marshal(p.Apply(u))
+	if err != nil {
+		return p.Apply(u)
+	}
+	var v map[string]interface{}
+	err = json.Unmarshal(b, &v)
+	if err != nil {
+		return p.Apply(u)
+	}
+	for field := range v {
+		if !p.Allows(field) {
+			delete(v, field)
+		}
+	}
+	return v
+}
+
+// projectedListResponse is user.ListResponse with projected users.
+type projectedListResponse struct {
+	Total      int64         `json:"total"`
+	PerPage    int64         `json:"per_page"`
+	Users      []interface{} `json:"users"`
+	NextCursor string        `json:"next_cursor"`
+}
+
+func projectList(p user.Projection, resp *user.ListResponse) interface{} {
+	if p == nil {
+		return resp
+	}
+	users := make([]interface{}, len(resp.Users))
+	for i, u := range resp.Users {
+		users[i] = projectUser(p, u)
+	}
+	return projectedListResponse{
+		Total:      resp.Total,
+		PerPage:    resp.PerPage,
+		Users:      users,
+		NextCursor: resp.NextCursor,
+	}
+}
+
+// projectFields returns the export fields allowed by p, fields defaults to
+// all of them.
+func projectFields(p user.Projection, fields []string) ([]string, error) {
+	if p == nil {
+		return fields, nil
+	}
+	if len(fields) == 0 {
+		for _, f := range user.ExportFields {
+			if p.Allows(f) {
+				fields = append(fields, f)
+			}
+		}
+		return fields, nil
+	}
+	for _, f := range fields {
+		if !p.Allows(f) {
+			return nil, &user.FieldError{
+				Err: user.Error{
+					Type:    user.PermissionDenied,
+					Code:    "field_not_allowed",
+					Message: fmt.Sprintf("API key isn't allowed to read field %q", f),
+				},
+				Field: "fields",
+			}
+		}
+	}
+	return fields, nil
+}
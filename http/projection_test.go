@@ -0,0 +1,129 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	uhttp "github.com/guilherme-santos/user/http"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjection(t *testing.T) {
+	u := &user.User{
+		ID:        "user-1",
+		FirstName: "John",
+		LastName:  "Doe",
+		Nickname:  "johndoe",
+		Email:     "john@doe.com",
+		Country:   "DE",
+	}
+
+	tests := []struct {
+		name   string
+		scopes []string
+		url    string
+		fields []string
+		status int
+		body   string
+	}{
+		{
+			name:   "get all fields",
+			scopes: []string{user.ScopeUsersRead, user.ScopeUsersReadPublic},
+			url:    "/users/user-1",
+			fields: []string{"id", "first_name", "last_name", "nickname", "email", "country", "created_at", "updated_at", "tenant_id"},
+			status: http.StatusOK,
+		},
+		{
+			name:   "get public fields",
+			scopes: []string{user.ScopeUsersReadPublic},
+			url:    "/users/user-1",
+			fields: []string{"id", "nickname", "country"},
+			status: http.StatusOK,
+		},
+		{
+			name:   "list public fields",
+			scopes: []string{user.ScopeUsersReadPublic},
+			url:    "/users",
+			fields: []string{"id", "nickname", "country"},
+			status: http.StatusOK,
+		},
+		{
+			name:   "export public fields",
+			scopes: []string{user.ScopeUsersReadPublic},
+			url:    "/users:export",
+			status: http.StatusOK,
+			body:   `{"country":"DE","id":"user-1","nickname":"johndoe"}` + "\n",
+		},
+		{
+			name:   "export personal field",
+			scopes: []string{user.ScopeUsersReadPublic},
+			url:    "/users:export?fields=id,email",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			keysvc := mock.NewAPIKeyService(ctrl)
+			keysvc.EXPECT().Authenticate(gomock.Any(), "usk_partner").Return(&user.APIKey{Name: "partner", Scopes: tt.scopes}, nil)
+
+			svc := mock.NewUserService(ctrl)
+			svc.EXPECT().Get(gomock.Any(), "user-1").Return(u, nil).AnyTimes()
+			svc.EXPECT().List(gomock.Any(), gomock.Any()).Return(&user.ListResponse{Total: 1, Users: []*user.User{u}}, nil).AnyTimes()
+			exportsvc := mock.NewExportService(ctrl)
+			exportsvc.EXPECT().
+				Export(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ *user.ListOptions, fn func(*user.User) error) error {
+					return fn(u)
+				}).
+				AnyTimes()
+
+			r := uhttp.NewRouter(nil, nil)
+			r.Use(uhttp.Authenticate(keysvc))
+			uhttp.NewUserHandler(r, svc)
+			uhttp.NewExportHandler(r, exportsvc)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			req.Header.Set(uhttp.APIKeyHeader, "usk_partner")
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+
+			if tt.body != "" {
+				assert.Equal(t, tt.body, w.Body.String())
+			}
+			if tt.fields == nil {
+				return
+			}
+			var got map[string]interface{}
+			if strings.HasSuffix(tt.url, "/users") {
+				var list struct {
+					Users []map[string]interface{} `json:"users"`
+				}
+				json.NewDecoder(w.Body).Decode(&list)
+				if !assert.Len(t, list.Users, 1) {
+					return
+				}
+				got = list.Users[0]
+			} else {
+				json.NewDecoder(w.Body).Decode(&got)
+			}
+			var fields []string
+			for f := range got {
+				fields = append(fields, f)
+			}
+			assert.ElementsMatch(t, tt.fields, fields)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/ratelimit"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// APIKeyHeader identifies the client performing the request.
+const APIKeyHeader = "X-API-Key"
+
+var ErrRateLimited = &user.Error{
+	Type:    user.ResourceExhausted,
+	Code:    "rate_limited",
+	Message: "Too many requests, retry later",
+}
+
+// RateLimitConfig contains the limits of each client, clients are identified
+// by ip and, once authenticated, by api key.
+type RateLimitConfig struct {
+	// IP is the limit of all requests of the same ip.
+	IP ratelimit.Limit
+	// APIKey is the limit of all requests authenticated by the same api key.
+	APIKey ratelimit.Limit
+	// Routes are limits by "METHOD pattern", e.g. "POST /v1/users", applied
+	// in addition to the client limit.
+	Routes ratelimit.Limits
+}
+
+type rateLimitCtx struct{}
+
+// RateLimit returns a middleware which takes a token of the ip bucket and, if
+// the route has its own limit, of the ip bucket of the route. It must run
+// before the authentication, so requests are limited whatever credentials
+// they carry. Requests are rejected with 429 when any of them is empty.
+// routes is used to find the route pattern, as it's only known after routing.
+// Failures of store are logged and the request is allowed.
+func RateLimit(routes chi.Routes, store ratelimit.Store, cfg RateLimitConfig) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			// RemoteAddr is already updated by RealIP
+			ip, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				ip = r.RemoteAddr
+			}
+			takeRateLimit(w, r, h, routes, store, cfg, "ip:"+ip, cfg.IP)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// RateLimitAPIKey returns a middleware which takes a token of the bucket of
+// the api key which authenticated the request and, if the route has its own
+// limit, of the api key bucket of the route. It must run after Authenticate
+// or AuthenticateAny, requests without api key are allowed.
+func RateLimitAPIKey(routes chi.Routes, store ratelimit.Store, cfg RateLimitConfig) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			k := user.APIKeyFrom(r.Context())
+			if k == nil {
+				h.ServeHTTP(w, r)
+				return
+			}
+			takeRateLimit(w, r, h, routes, store, cfg, "key:"+k.ID, cfg.APIKey)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// takeRateLimit takes a token of the client bucket and of the client bucket of
+// the route, then calls h or rejects the request. The headers report the most
+// restrictive result, including the ones of previous middlewares.
+func takeRateLimit(w http.ResponseWriter, r *http.Request, h http.Handler, routes chi.Routes, store ratelimit.Store, cfg RateLimitConfig, client string, limit ratelimit.Limit) {
+	ctx := r.Context()
+
+	type bucket struct {
+		key   string
+		limit ratelimit.Limit
+	}
+	buckets := []bucket{{key: client, limit: limit}}
+
+	rctx := chi.NewRouteContext()
+	if routes.Match(rctx, r.Method, r.URL.Path) {
+		route := r.Method + " " + rctx.RoutePattern()
+		if limit, ok := cfg.Routes[route]; ok {
+			buckets = append(buckets, bucket{key: client + ":" + route, limit: limit})
+		}
+	}
+
+	// the most restrictive result is reported
+	res, _ := ctx.Value(rateLimitCtx{}).(*ratelimit.Result)
+	for _, b := range buckets {
+		if b.limit.Disabled() {
+			continue
+		}
+		bres, err := store.Take(ctx, b.key, b.limit)
+		if err != nil {
+			user.Logger(ctx).WithError(err).Error("unable to take rate limit token")
+			continue
+		}
+		if res == nil || (res.Allowed && !bres.Allowed) || (res.Allowed == bres.Allowed && bres.Remaining < res.Remaining) {
+			res = bres
+		}
+	}
+	if res == nil {
+		h.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	w.Header().Set("RateLimit-Reset", ceilSeconds(res.Reset))
+	if !res.Allowed {
+		w.Header().Set("Retry-After", ceilSeconds(res.RetryAfter))
+		respondWithError(w, ErrRateLimited)
+		return
+	}
+	h.ServeHTTP(w, r.WithContext(context.WithValue(ctx, rateLimitCtx{}, res)))
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
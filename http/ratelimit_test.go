@@ -0,0 +1,123 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/guilherme-santos/user"
+	uhttp "github.com/guilherme-santos/user/http"
+	"github.com/guilherme-santos/user/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	r := uhttp.NewRouter(nil, nil)
+	r.Use(uhttp.RateLimit(r, ratelimit.NewMemoryStore(), uhttp.RateLimitConfig{
+		IP:     ratelimit.Every(3, time.Minute),
+		APIKey: ratelimit.Every(10, time.Minute),
+		Routes: ratelimit.Limits{
+			"POST /users": ratelimit.Every(1, time.Minute),
+		},
+	}))
+	r.Get("/users", func(w http.ResponseWriter, req *http.Request) {})
+	r.Post("/users", func(w http.ResponseWriter, req *http.Request) {})
+
+	do := func(method, apiKey string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/users", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		if apiKey != "" {
+			req.Header.Set(uhttp.APIKeyHeader, apiKey)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// route limit is the most restrictive
+	w := do(http.MethodPost, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+	w = do(http.MethodPost, "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"code":"rate_limited","message":"Too many requests, retry later"}`, w.Body.String())
+
+	// ip limit is shared by all routes, rejected requests also take a token
+	w = do(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "3", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+	w = do(http.MethodGet, "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	// api keys aren't validated yet, they don't get a bucket of their own
+	w = do(http.MethodGet, "random")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	// forwarded headers of untrusted peers don't get a bucket of their own
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/users", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
+
+func TestRateLimitAPIKey(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	cfg := uhttp.RateLimitConfig{
+		IP:     ratelimit.Every(10, time.Minute),
+		APIKey: ratelimit.Every(2, time.Minute),
+	}
+	r := uhttp.NewRouter(nil, nil)
+	r.Use(uhttp.RateLimit(r, store, cfg))
+	r.Use(func(h http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, req *http.Request) {
+			if id := req.Header.Get(uhttp.APIKeyHeader); id != "" {
+				req = req.WithContext(user.SetAPIKey(req.Context(), &user.APIKey{ID: id}))
+			}
+			h.ServeHTTP(w, req)
+		}
+		return http.HandlerFunc(fn)
+	})
+	r.Use(uhttp.RateLimitAPIKey(r, store, cfg))
+	r.Get("/users", func(w http.ResponseWriter, req *http.Request) {})
+
+	do := func(keyID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/users", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		if keyID != "" {
+			req.Header.Set(uhttp.APIKeyHeader, keyID)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// the key limit is the most restrictive
+	w := do("key1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+
+	w = do("key1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = do("key1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	// other keys have their own bucket
+	w = do("key2")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// requests without key are only limited by ip
+	w = do("")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "10", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "5", w.Header().Get("RateLimit-Remaining"))
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// RealIP returns a middleware which replaces RemoteAddr by the ip of the
+// client from the X-Forwarded-For or X-Real-IP headers, only when the request
+// comes from one of the trusted proxies. Otherwise the headers are ignored, as
+// any client can set them, e.g. to get a fresh rate limit bucket.
+// X-Forwarded-For is read from right to left and the first address which
+// isn't a trusted proxy is the client.
+func RealIP(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if peer, ok := parseIP(r.RemoteAddr); ok && isTrusted(trusted, peer) {
+				if ip, ok := forwardedIP(trusted, r.Header); ok {
+					r.RemoteAddr = ip.String()
+				}
+			}
+			h.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// forwardedIP returns the ip of the client forwarded by a trusted proxy.
+func forwardedIP(trusted []netip.Prefix, header http.Header) (netip.Addr, bool) {
+	var hops []string
+	for _, v := range header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+	if len(hops) == 0 {
+		return parseIP(header.Get("X-Real-IP"))
+	}
+
+	var client netip.Addr
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip, ok := parseIP(hops[i])
+		if !ok {
+			// hops on the left of an invalid one can't be trusted
+			break
+		}
+		client = ip
+		if !isTrusted(trusted, ip) {
+			break
+		}
+	}
+	return client, client.IsValid()
+}
+
+// parseIP parses addr with or without the port.
+func parseIP(addr string) (netip.Addr, bool) {
+	addr = strings.TrimSpace(addr)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return ip.Unmap(), true
+}
+
+func isTrusted(trusted []netip.Prefix, ip netip.Addr) bool {
+	for _, p := range trusted {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -8,7 +8,10 @@ import (
 	"github.com/guilherme-santos/user"
 )
 
-var JSONContentType = "application/json; charset=UTF-8"
+var (
+	JSONContentType   = "application/json; charset=UTF-8"
+	NDJSONContentType = "application/x-ndjson; charset=UTF-8"
+)
 
 func respond(w http.ResponseWriter, status int, body interface{}) {
 	if body != nil {
@@ -33,6 +36,12 @@ func respondNoContent(w http.ResponseWriter) {
 }
 
 func respondWithError(w http.ResponseWriter, err error) {
+	status, body := errorResponse(err)
+	respond(w, status, body)
+}
+
+// errorResponse returns the status code and the body which represents err.
+func errorResponse(err error) (int, error) {
 	var uerr *user.Error
 	if !errors.As(err, &uerr) {
 		uerr = &user.Error{
@@ -50,14 +59,26 @@ func respondWithError(w http.ResponseWriter, err error) {
 		status = http.StatusBadRequest
 	case user.NotFound:
 		status = http.StatusNotFound
+	case user.ResourceExhausted:
+		status = http.StatusTooManyRequests
+	case user.Unauthenticated:
+		status = http.StatusUnauthorized
+	case user.PermissionDenied:
+		status = http.StatusForbidden
+	case user.Conflict:
+		status = http.StatusConflict
+	case user.Unprocessable:
+		status = http.StatusUnprocessableEntity
 	default:
 		status = http.StatusInternalServerError
 	}
-
-	respond(w, status, err)
+	return status, err
 }
 
 func newJSONDecodeError(err error) *user.Error {
+	if isBodyTooLarge(err) {
+		return ErrBodyTooLarge
+	}
 	return &user.Error{
 		Type:    user.InvalidArgument,
 		Code:    "invalid_json",
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/guilherme-santos/user"
+)
+
+type RoleHandler struct {
+	svc user.RoleService
+}
+
+func NewRoleHandler(r chi.Router, svc user.RoleService) *RoleHandler {
+	h := &RoleHandler{
+		svc: svc,
+	}
+	r.With(RequireScope(user.ScopeUsersRead)).Get("/users/{id}/roles", h.List)
+	r.With(RequireScope(user.ScopeRolesWrite)).Put("/users/{id}/roles/{role}", h.Grant)
+	r.With(RequireScope(user.ScopeRolesWrite)).Delete("/users/{id}/roles/{role}", h.Revoke)
+	return h
+}
+
+// RoleListResponse contains the roles returned by List.
+type RoleListResponse struct {
+	Roles []*user.RoleAssignment `json:"roles"`
+}
+
+func (h RoleHandler) List(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+	roles, err := h.svc.ListRoles(req.Context(), id)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	respondOK(w, RoleListResponse{Roles: roles})
+}
+
+func (h RoleHandler) Grant(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+	role := chi.URLParam(req, "role")
+	ra, err := h.svc.GrantRole(req.Context(), id, role)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	respondOK(w, ra)
+}
+
+func (h RoleHandler) Revoke(w http.ResponseWriter, req *http.Request) {
+	id := chi.URLParam(req, "id")
+	role := chi.URLParam(req, "role")
+	err := h.svc.RevokeRole(req.Context(), id, role)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	respondNoContent(w)
+}
@@ -0,0 +1,95 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	uhttp "github.com/guilherme-santos/user/http"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		expect func(*mock.RoleService)
+		status int
+	}{
+		{
+			name:   "list",
+			method: http.MethodGet,
+			url:    "/users/user-1/roles",
+			expect: func(svc *mock.RoleService) {
+				svc.EXPECT().ListRoles(gomock.Any(), "user-1").Return([]*user.RoleAssignment{{UserID: "user-1", Role: user.RoleAdmin}}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "grant",
+			method: http.MethodPut,
+			url:    "/users/user-1/roles/admin",
+			expect: func(svc *mock.RoleService) {
+				svc.EXPECT().GrantRole(gomock.Any(), "user-1", user.RoleAdmin).Return(&user.RoleAssignment{UserID: "user-1", Role: user.RoleAdmin}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "grant forbidden",
+			method: http.MethodPut,
+			url:    "/users/user-1/roles/admin",
+			expect: func(svc *mock.RoleService) {
+				svc.EXPECT().GrantRole(gomock.Any(), "user-1", user.RoleAdmin).Return(nil, user.ErrForbidden)
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "revoke",
+			method: http.MethodDelete,
+			url:    "/users/user-1/roles/admin",
+			expect: func(svc *mock.RoleService) {
+				svc.EXPECT().RevokeRole(gomock.Any(), "user-1", user.RoleAdmin).Return(nil)
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "revoke not granted",
+			method: http.MethodDelete,
+			url:    "/users/user-1/roles/support",
+			expect: func(svc *mock.RoleService) {
+				svc.EXPECT().RevokeRole(gomock.Any(), "user-1", user.RoleSupport).Return(user.ErrRoleNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := mock.NewRoleService(ctrl)
+			tt.expect(svc)
+
+			r := uhttp.NewRouter(nil, nil)
+			uhttp.NewRoleHandler(r, svc)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.url, nil)
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+
+			if tt.method == http.MethodGet {
+				var body uhttp.RoleListResponse
+				json.NewDecoder(w.Body).Decode(&body)
+				assert.Len(t, body.Roles, 1)
+			}
+		})
+	}
+}
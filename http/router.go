@@ -3,34 +3,99 @@ package http
 import (
 	"net"
 	"net/http"
+	"net/netip"
+	"strconv"
 	"time"
 
 	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/metrics"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/sirupsen/logrus"
 )
 
-func NewRouter(logger logrus.FieldLogger) chi.Router {
+// NewRouter returns a router with the middlewares of every request, the
+// client ip is only taken from forwarded headers of trustedProxies.
+func NewRouter(logger logrus.FieldLogger, trustedProxies []netip.Prefix) chi.Router {
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(RealIP(trustedProxies))
+	r.Use(RequestContext)
+	r.Use(Tracing)
 	if logger != nil {
 		r.Use(Logger(logger))
 	} else {
 		r.Use(middleware.Logger)
 	}
 	r.Use(middleware.Recoverer)
+	r.Use(Metrics)
 	return r
 }
 
-// Logger returns a request logging middleware
+// ActorHeader identifies who is performing the request when it isn't
+// authenticated, e.g. USERSVC_AUTH_ENABLED=false.
+const ActorHeader = "X-Actor"
+
+// RequestContext is a middleware which stores the request id and the actor
+// of the request in the context. The actor of ActorHeader is replaced by the
+// principal authenticated by the authentication middlewares, which run after
+// it, so the header can't be used to impersonate them. It's never logged as
+// principal.
+func RequestContext(h http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if reqID := middleware.GetReqID(ctx); reqID != "" {
+			ctx = user.SetRequestID(ctx, reqID)
+		}
+		if actor := r.Header.Get(ActorHeader); actor != "" {
+			ctx = user.SetActor(ctx, actor)
+		}
+		h.ServeHTTP(w, r.WithContext(ctx))
+	}
+	return http.HandlerFunc(fn)
+}
+
+// Metrics is a middleware which records the count and latency of requests
+// per route pattern and status code.
+func Metrics(h http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		t1 := time.Now()
+		defer func() {
+			// route pattern is only known after routing, unmatched requests
+			// are grouped to avoid high cardinality.
+			route := "unmatched"
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			status := strconv.Itoa(ww.Status())
+			metrics.HTTPRequests.WithLabelValues(r.Method, route, status).Inc()
+			metrics.HTTPDuration.WithLabelValues(r.Method, route, status).Observe(time.Since(t1).Seconds())
+		}()
+
+		h.ServeHTTP(ww, r)
+	}
+	return http.HandlerFunc(fn)
+}
+
+// Logger returns a request logging middleware, it also stores in the context
+// a logger carrying the request id and route pattern of the request. The
+// principal authenticated by the API key or access token is added by the
+// authentication middlewares, the access log also has it.
 func Logger(log logrus.FieldLogger) func(h http.Handler) http.Handler {
 	return func(h http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
-			ctx := user.SetLogger(r.Context(), log)
+			ctx := r.Context()
 			reqID := middleware.GetReqID(ctx)
+			fields := logrus.Fields{
+				"route": routeField{chi.RouteContext(ctx)},
+			}
+			if len(reqID) > 0 {
+				fields["request_id"] = reqID
+			}
+			ctx = user.WithPrincipal(ctx)
+			ctx = user.SetLogger(ctx, log.WithFields(fields))
 			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
 			t1 := time.Now()
 			defer func() {
@@ -44,20 +109,21 @@ func Logger(log logrus.FieldLogger) func(h http.Handler) http.Handler {
 				}
 				elapsed := time.Since(t1)
 
-				log := user.Logger(ctx)
-				fields := logrus.Fields{
-					"status_code": ww.Status(),
-					"bytes":       ww.BytesWritten(),
-					"elapsed_ms":  float64(elapsed.Nanoseconds()) / float64(time.Millisecond),
-					"elapsed":     elapsed.String(),
-					"remote_ip":   remoteIP,
-					"proto":       r.Proto,
-					"method":      r.Method,
-				}
-				if len(reqID) > 0 {
-					fields["request_id"] = reqID
+				entry := user.Logger(ctx)
+				if principal := user.Principal(ctx); principal != "" {
+					entry = entry.WithField("principal", principal)
 				}
-				log.WithFields(fields).Infof("%s://%s%s", scheme, r.Host, r.RequestURI)
+				entry.
+					WithFields(logrus.Fields{
+						"status_code": ww.Status(),
+						"bytes":       ww.BytesWritten(),
+						"elapsed_ms":  float64(elapsed.Nanoseconds()) / float64(time.Millisecond),
+						"elapsed":     elapsed.String(),
+						"remote_ip":   remoteIP,
+						"proto":       r.Proto,
+						"method":      r.Method,
+					}).
+					Infof("%s://%s%s", scheme, r.Host, r.RequestURI)
 			}()
 
 			h.ServeHTTP(ww, r.WithContext(ctx))
@@ -66,3 +132,20 @@ func Logger(log logrus.FieldLogger) func(h http.Handler) http.Handler {
 		return http.HandlerFunc(fn)
 	}
 }
+
+// routeField is resolved when the entry is formatted, as the route pattern is
+// only known after routing.
+type routeField struct {
+	rctx *chi.Context
+}
+
+func (f routeField) String() string {
+	if f.rctx == nil || f.rctx.RoutePattern() == "" {
+		return "unmatched"
+	}
+	return f.rctx.RoutePattern()
+}
+
+func (f routeField) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
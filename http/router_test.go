@@ -0,0 +1,120 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	uhttp "github.com/guilherme-santos/user/http"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// authenticateAs is a middleware which authenticates every request as
+// principal, like the authentication middlewares do.
+func authenticateAs(principal string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			h.ServeHTTP(w, req.WithContext(user.SetPrincipal(req.Context(), principal)))
+		})
+	}
+}
+
+func TestLoggerContextFields(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	r := uhttp.NewRouter(logger, nil)
+	r.With(authenticateAs("apikey:admin")).Get("/users/{id}", func(w http.ResponseWriter, req *http.Request) {
+		user.Logger(req.Context()).Info("inside handler")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/users/uuid", nil)
+	r.ServeHTTP(w, req)
+
+	// handler entry and access log
+	entries := hook.AllEntries()
+	if !assert.Len(t, entries, 2) {
+		return
+	}
+	for _, entry := range entries {
+		line, err := (&logrus.JSONFormatter{}).Format(entry)
+		assert.NoError(t, err)
+		assert.Contains(t, string(line), `"route":"/users/{id}"`)
+		assert.Contains(t, string(line), `"principal":"apikey:admin"`)
+		assert.NotEmpty(t, entry.Data["request_id"])
+	}
+}
+
+func TestActorHeader(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	r := uhttp.NewRouter(logger, nil)
+	r.Get("/users/{id}", func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "admin", user.Actor(req.Context()))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	r.With(authenticateAs("apikey:backoffice")).Put("/users/{id}", func(w http.ResponseWriter, req *http.Request) {
+		// the authenticated principal can't be replaced by the header
+		assert.Equal(t, "apikey:backoffice", user.Actor(req.Context()))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	for _, method := range []string{http.MethodGet, http.MethodPut} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/users/uuid", nil)
+		req.Header.Set(uhttp.ActorHeader, "admin")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	}
+
+	// the claimed actor is never logged as principal
+	if assert.Len(t, hook.AllEntries(), 2) {
+		assert.NotContains(t, hook.AllEntries()[0].Data, "principal")
+		assert.Equal(t, "apikey:backoffice", hook.AllEntries()[1].Data["principal"])
+	}
+}
+
+func TestRealIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tt := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		realIP     string
+		want       string
+	}{
+		{"untrusted peer", "203.0.113.7:1234", "198.51.100.1", "198.51.100.2", "203.0.113.7:1234"},
+		{"trusted proxy", "10.0.0.1:1234", "198.51.100.1", "", "198.51.100.1"},
+		{"client spoofing behind proxy", "10.0.0.1:1234", "192.0.2.1, 198.51.100.1", "", "198.51.100.1"},
+		{"chain of proxies", "10.0.0.1:1234", "198.51.100.1, 10.0.0.2", "", "198.51.100.1"},
+		{"invalid hop", "10.0.0.1:1234", "198.51.100.1, unknown", "", "10.0.0.1:1234"},
+		{"real ip header", "10.0.0.1:1234", "", "198.51.100.1", "198.51.100.1"},
+		{"no headers", "10.0.0.1:1234", "", "", "10.0.0.1:1234"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var got string
+			h := uhttp.RealIP(trusted)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				got = req.RemoteAddr
+			}))
+
+			req, _ := http.NewRequest(http.MethodGet, "/users", nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tc.forwarded)
+			}
+			if tc.realIP != "" {
+				req.Header.Set("X-Real-IP", tc.realIP)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -1,20 +1,110 @@
 package http
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"time"
+
+	"github.com/guilherme-santos/user"
 )
 
-// As a improvement we could pass a http.ServerConfig with more options
-// and to avoid to have so many parameters.
+// ServerConfig contains the settings of the http server.
+type ServerConfig struct {
+	Addr              string
+	ReadHeaderTimeout time.Duration
+	// ReadTimeout and WriteTimeout don't apply to streaming routes, e.g.
+	// import and export, which run as long as the client keeps reading or
+	// writing the body.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// MaxHeaderBytes zero uses http.DefaultMaxHeaderBytes.
+	MaxHeaderBytes int
+	// MaxBodyBytes limits the body of requests, except of streaming routes,
+	// zero disables it.
+	MaxBodyBytes int64
+	// KeepAlive reuses connections between requests.
+	KeepAlive bool
+}
 
-func NewServer(addr string, router http.Handler) http.Server {
-	return http.Server{
-		Addr:              addr,
-		ReadTimeout:       1 * time.Second,
-		WriteTimeout:      1 * time.Second,
-		IdleTimeout:       30 * time.Second,
+// DefaultServerConfig returns the default settings of the http server.
+func DefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		Addr:              "0.0.0.0:80",
 		ReadHeaderTimeout: 2 * time.Second,
-		Handler:           router,
+		ReadTimeout:       5 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		IdleTimeout:       30 * time.Second,
+		MaxHeaderBytes:    http.DefaultMaxHeaderBytes,
+		MaxBodyBytes:      1 << 20,
+		KeepAlive:         true,
+	}
+}
+
+var ErrBodyTooLarge = &user.Error{
+	Type:    user.InvalidArgument,
+	Code:    "body_too_large",
+	Message: "Request body is too large",
+}
+
+func NewServer(cfg ServerConfig, router http.Handler) *http.Server {
+	h := router
+	if cfg.MaxBodyBytes > 0 {
+		h = limitBody(cfg.MaxBodyBytes)(h)
+	}
+	srv := &http.Server{
+		Addr:              cfg.Addr,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		Handler:           h,
 	}
+	srv.SetKeepAlivesEnabled(cfg.KeepAlive)
+	return srv
+}
+
+// limitedBody is a body limited by limitBody, it keeps the original body so
+// streaming routes can lift the limit.
+type limitedBody struct {
+	io.ReadCloser
+	body io.ReadCloser
+}
+
+// limitBody returns a middleware which limits the body of requests to n bytes.
+func limitBody(n int64) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = &limitedBody{
+					ReadCloser: http.MaxBytesReader(w, r.Body, n),
+					body:       r.Body,
+				}
+			}
+			h.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// stream lifts the body limit and the read and write deadlines of the server
+// from a request, so streaming routes run as long as the client keeps
+// reading or writing the body.
+func stream(w http.ResponseWriter, r *http.Request) {
+	if b, ok := r.Body.(*limitedBody); ok {
+		r.Body = b.body
+	}
+	rc := http.NewResponseController(w)
+	// zero time means no deadline, it fails for writers without deadlines,
+	// e.g. httptest.ResponseRecorder, which don't need it
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+}
+
+// isBodyTooLarge returns whether err is caused by a body over the limit.
+func isBodyTooLarge(err error) bool {
+	var merr *http.MaxBytesError
+	return errors.As(err, &merr)
 }
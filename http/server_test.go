@@ -0,0 +1,54 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerMaxBodyBytes(t *testing.T) {
+	r := NewRouter(nil, nil)
+	r.Post("/users", func(w http.ResponseWriter, req *http.Request) {
+		var v interface{}
+		err := json.NewDecoder(req.Body).Decode(&v)
+		if err != nil {
+			respondWithError(w, newJSONDecodeError(err))
+			return
+		}
+		respondOK(w, v)
+	})
+	r.Post("/users:import", func(w http.ResponseWriter, req *http.Request) {
+		stream(w, req)
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			respondWithError(w, err)
+			return
+		}
+		respondOK(w, len(body))
+	})
+	srv := NewServer(ServerConfig{MaxBodyBytes: 16}, r)
+
+	do := func(path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		srv.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("/users", `{"a":"b"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = do("/users", `{"a":"`+strings.Repeat("b", 16)+`"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"code":"body_too_large","message":"Request body is too large"}`, w.Body.String())
+
+	// streaming routes aren't limited
+	w = do("/users:import", strings.Repeat("b", 32))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "32\n", w.Body.String())
+}
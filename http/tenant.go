@@ -0,0 +1,39 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/guilherme-santos/user"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TenantHeader selects the tenant of the request.
+const TenantHeader = "X-Tenant-ID"
+
+// Tenant is a middleware which stores in the context the tenant of the
+// X-Tenant-ID header, user.DefaultTenant is used when it's missing.
+// Authenticate and BearerAuth replace it by the tenant of the key or the
+// token, so the header only matters for keys not bound to a tenant.
+func Tenant(h http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		tenant := r.Header.Get(TenantHeader)
+		if tenant == "" {
+			tenant = user.DefaultTenant
+		}
+		err := user.ValidateTenant(tenant)
+		if err != nil {
+			respondWithError(w, err)
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(withTenant(r.Context(), tenant)))
+	}
+	return http.HandlerFunc(fn)
+}
+
+// withTenant stores tenant in ctx and adds it to the logger.
+func withTenant(ctx context.Context, tenant string) context.Context {
+	ctx = user.SetTenant(ctx, tenant)
+	return user.WithLogFields(ctx, logrus.Fields{"tenant": tenant})
+}
@@ -0,0 +1,92 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	uhttp "github.com/guilherme-santos/user/http"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenant(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		keyTenant string
+		tenant    string
+		status    int
+	}{
+		{
+			name:   "default tenant",
+			tenant: user.DefaultTenant,
+			status: http.StatusOK,
+		},
+		{
+			name:   "tenant from header",
+			header: "brand-1",
+			tenant: "brand-1",
+			status: http.StatusOK,
+		},
+		{
+			name:      "tenant from key",
+			keyTenant: "brand-1",
+			tenant:    "brand-1",
+			status:    http.StatusOK,
+		},
+		{
+			// keys bound to a tenant can't select another one
+			name:      "header ignored",
+			header:    "brand-2",
+			keyTenant: "brand-1",
+			tenant:    "brand-1",
+			status:    http.StatusOK,
+		},
+		{
+			name:   "invalid tenant",
+			header: "Brand 1",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			keysvc := mock.NewAPIKeyService(ctrl)
+			keysvc.EXPECT().
+				Authenticate(gomock.Any(), "usk_valid").
+				Return(&user.APIKey{Name: "backoffice", Scopes: user.Scopes, TenantID: tt.keyTenant}, nil).
+				AnyTimes()
+			svc := mock.NewUserService(ctrl)
+			if tt.status == http.StatusOK {
+				svc.EXPECT().
+					Get(gomock.Any(), "user-1").
+					DoAndReturn(func(ctx context.Context, id string) (*user.User, error) {
+						assert.Equal(t, tt.tenant, user.Tenant(ctx))
+						return &user.User{ID: id, TenantID: user.Tenant(ctx)}, nil
+					})
+			}
+
+			r := uhttp.NewRouter(nil, nil)
+			r.Use(uhttp.Tenant)
+			r.Use(uhttp.Authenticate(keysvc))
+			uhttp.NewUserHandler(r, svc)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/users/user-1", nil)
+			req.Header.Set(uhttp.APIKeyHeader, "usk_valid")
+			if tt.header != "" {
+				req.Header.Set(uhttp.TenantHeader, tt.header)
+			}
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
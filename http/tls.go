@@ -0,0 +1,171 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/guilherme-santos/user"
+)
+
+// TLSConfig contains the files of the server certificate, the server speaks
+// plaintext when CertFile is empty.
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile enables mTLS, client certificates are verified against
+	// the CAs in it.
+	ClientCAFile string
+	// RequireClientCert rejects clients without a certificate, otherwise
+	// it's only verified when provided.
+	RequireClientCert bool
+}
+
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+// CertReloader keeps the certificate and client CAs of TLSConfig, reloading
+// them when the files change. Connections already established keep the
+// certificate they were opened with, so nothing is dropped.
+type CertReloader struct {
+	cfg TLSConfig
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+	modTimes []time.Time
+}
+
+// NewCertReloader returns a CertReloader with the files of cfg loaded.
+func NewCertReloader(cfg TLSConfig) (*CertReloader, error) {
+	cr := &CertReloader{cfg: cfg}
+	err := cr.Reload()
+	if err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+// Reload reads the files again, the current certificate is kept on failure.
+func (cr *CertReloader) Reload() error {
+	modTimes, err := cr.stat()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(cr.cfg.CertFile, cr.cfg.KeyFile)
+	if err != nil {
+		return err
+	}
+	var clientCA *x509.CertPool
+	if cr.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cr.cfg.ClientCAFile)
+		if err != nil {
+			return err
+		}
+		clientCA = x509.NewCertPool()
+		if !clientCA.AppendCertsFromPEM(pem) {
+			return errors.New("no certificate found in " + cr.cfg.ClientCAFile)
+		}
+	}
+
+	cr.mu.Lock()
+	cr.cert = &cert
+	cr.clientCA = clientCA
+	cr.modTimes = modTimes
+	cr.mu.Unlock()
+	return nil
+}
+
+// WatchEvery reloads the files when they change, checking them every
+// interval until ctx is done.
+func (cr *CertReloader) WatchEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !cr.changed() {
+			continue
+		}
+		err := cr.Reload()
+		if err != nil {
+			// files may be half written, it's retried in the next tick
+			user.Logger(ctx).WithError(err).Error("unable to reload tls certificate")
+			continue
+		}
+		user.Logger(ctx).Info("tls certificate reloaded")
+	}
+}
+
+// TLSConfig returns a tls.Config which always uses the latest files loaded.
+func (cr *CertReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cr.mu.RLock()
+			defer cr.mu.RUnlock()
+
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cr.cert},
+			}
+			if cr.clientCA != nil {
+				cfg.ClientCAs = cr.clientCA
+				cfg.ClientAuth = tls.VerifyClientCertIfGiven
+				if cr.cfg.RequireClientCert {
+					cfg.ClientAuth = tls.RequireAndVerifyClientCert
+				}
+			}
+			return cfg, nil
+		},
+	}
+}
+
+func (cr *CertReloader) files() []string {
+	files := []string{cr.cfg.CertFile, cr.cfg.KeyFile}
+	if cr.cfg.ClientCAFile != "" {
+		files = append(files, cr.cfg.ClientCAFile)
+	}
+	return files
+}
+
+func (cr *CertReloader) stat() ([]time.Time, error) {
+	files := cr.files()
+	modTimes := make([]time.Time, len(files))
+	for i, f := range files {
+		fi, err := os.Stat(f)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[i] = fi.ModTime()
+	}
+	return modTimes, nil
+}
+
+// changed returns whether any file was modified since the last reload.
+func (cr *CertReloader) changed() bool {
+	modTimes, err := cr.stat()
+	if err != nil {
+		return false
+	}
+
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	for i, t := range modTimes {
+		if !t.Equal(cr.modTimes[i]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package http_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	uhttp "github.com/guilherme-santos/user/http"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCert returns a self-signed certificate for cn as PEM.
+func newCert(t *testing.T, cn string) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		DNSNames:              []string{cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	cfg := uhttp.TLSConfig{
+		CertFile:     filepath.Join(dir, "tls.crt"),
+		KeyFile:      filepath.Join(dir, "tls.key"),
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+	}
+	write := func(cn string) {
+		cert, key := newCert(t, cn)
+		require.NoError(t, os.WriteFile(cfg.CertFile, cert, 0600))
+		require.NoError(t, os.WriteFile(cfg.KeyFile, key, 0600))
+	}
+	write("v1.example.com")
+	clientCert, clientKey := newCert(t, "client")
+	require.NoError(t, os.WriteFile(cfg.ClientCAFile, clientCert, 0600))
+
+	certs, err := uhttp.NewCertReloader(cfg)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if len(req.TLS.PeerCertificates) > 0 {
+			w.Write([]byte(req.TLS.PeerCertificates[0].Subject.CommonName))
+		}
+	}))
+	srv.TLS = certs.TLSConfig()
+	srv.StartTLS()
+	defer srv.Close()
+
+	// serverName returns the certificate presented by the server to a new
+	// connection.
+	serverName := func(certs ...tls.Certificate) (string, error) {
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true, Certificates: certs},
+		}}
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		return resp.TLS.PeerCertificates[0].Subject.CommonName, nil
+	}
+
+	cn, err := serverName()
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.example.com", cn)
+
+	write("v2.example.com")
+	require.NoError(t, certs.Reload())
+	cn, err = serverName()
+	assert.NoError(t, err)
+	assert.Equal(t, "v2.example.com", cn)
+
+	// client certificates are verified when provided
+	cc, err := tls.X509KeyPair(clientCert, clientKey)
+	require.NoError(t, err)
+	_, err = serverName(cc)
+	assert.NoError(t, err)
+
+	other, otherKey := newCert(t, "client")
+	oc, err := tls.X509KeyPair(other, otherKey)
+	require.NoError(t, err)
+	_, err = serverName(oc)
+	assert.Error(t, err)
+}
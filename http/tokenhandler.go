@@ -0,0 +1,75 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/guilherme-santos/user"
+)
+
+// Grant types accepted by the token endpoint.
+const (
+	GrantTypePassword     = "password"
+	GrantTypeRefreshToken = "refresh_token"
+)
+
+type TokenHandler struct {
+	svc user.TokenService
+}
+
+func NewTokenHandler(r chi.Router, svc user.TokenService) *TokenHandler {
+	h := &TokenHandler{
+		svc: svc,
+	}
+	r.Post("/auth/token", h.Token)
+	return h
+}
+
+// TokenRequest is the body of the token endpoint, Email and Password are
+// used by the password grant and RefreshToken by the refresh_token one.
+type TokenRequest struct {
+	GrantType    string `json:"grant_type"`
+	Email        string `json:"email,omitempty"`
+	Password     string `json:"password,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+func (h TokenHandler) Token(w http.ResponseWriter, req *http.Request) {
+	var treq TokenRequest
+
+	err := json.NewDecoder(req.Body).Decode(&treq)
+	if err != nil {
+		respondWithError(w, newJSONDecodeError(err))
+		return
+	}
+
+	ctx := req.Context()
+
+	var t *user.Token
+	switch treq.GrantType {
+	case GrantTypePassword:
+		t, err = h.svc.IssueToken(ctx, treq.Email, treq.Password)
+	case GrantTypeRefreshToken:
+		t, err = h.svc.RefreshToken(ctx, treq.RefreshToken)
+	case "":
+		err = user.NewMissingFieldError("grant_type")
+	default:
+		err = &user.FieldError{
+			Err: user.Error{
+				Type:    user.InvalidArgument,
+				Code:    "unsupported_grant_type",
+				Message: "Grant type must be one of password, refresh_token",
+			},
+			Field: "grant_type",
+		}
+	}
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// tokens must never be cached
+	w.Header().Set("Cache-Control", "no-store")
+	respondOK(w, t)
+}
@@ -0,0 +1,111 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	uhttp "github.com/guilherme-santos/user/http"
+	"github.com/guilherme-santos/user/keyring"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenHandler(t *testing.T) {
+	tok := &user.Token{AccessToken: "access", TokenType: "Bearer", ExpiresIn: 900, RefreshToken: "refresh"}
+
+	tests := []struct {
+		name   string
+		body   string
+		expect func(*mock.TokenService)
+		status int
+		code   string
+	}{
+		{
+			name: "password",
+			body: `{"grant_type":"password","email":"john@doe.com","password":"secret"}`,
+			expect: func(svc *mock.TokenService) {
+				svc.EXPECT().IssueToken(gomock.Any(), "john@doe.com", "secret").Return(tok, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "wrong password",
+			body: `{"grant_type":"password","email":"john@doe.com","password":"wrong"}`,
+			expect: func(svc *mock.TokenService) {
+				svc.EXPECT().IssueToken(gomock.Any(), "john@doe.com", "wrong").Return(nil, user.ErrInvalidCredentials)
+			},
+			status: http.StatusUnauthorized,
+			code:   "invalid_credentials",
+		},
+		{
+			name: "refresh token",
+			body: `{"grant_type":"refresh_token","refresh_token":"refresh"}`,
+			expect: func(svc *mock.TokenService) {
+				svc.EXPECT().RefreshToken(gomock.Any(), "refresh").Return(tok, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "unsupported grant type",
+			body:   `{"grant_type":"client_credentials"}`,
+			status: http.StatusBadRequest,
+			code:   "unsupported_grant_type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := mock.NewTokenService(ctrl)
+			if tt.expect != nil {
+				tt.expect(svc)
+			}
+
+			r := uhttp.NewRouter(nil, nil)
+			uhttp.NewTokenHandler(r, svc)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/auth/token", strings.NewReader(tt.body))
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			var body map[string]interface{}
+			json.NewDecoder(w.Body).Decode(&body)
+			if tt.code != "" {
+				assert.Equal(t, tt.code, body["code"])
+			} else {
+				assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+				assert.Equal(t, "access", body["access_token"])
+				assert.Equal(t, "refresh", body["refresh_token"])
+			}
+		})
+	}
+}
+
+func TestJWKSHandler(t *testing.T) {
+	kr, err := keyring.Open(t.TempDir())
+	require.NoError(t, err)
+
+	r := uhttp.NewRouter(nil, nil)
+	uhttp.NewJWKSHandler(r, kr)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var set keyring.JWKS
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&set))
+	if assert.Len(t, set.Keys, 1) {
+		assert.Equal(t, kr.JWKS().Keys[0], set.Keys[0])
+	}
+}
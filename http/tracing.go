@@ -0,0 +1,51 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/guilherme-santos/user/http")
+
+// Tracing is a middleware which starts a server span for every request, as
+// child of the W3C trace context received in the headers, if any.
+func Tracing(h http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		if reqID := middleware.GetReqID(ctx); reqID != "" {
+			span.SetAttributes(attribute.String("http.request.id", reqID))
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		defer func() {
+			// route pattern is only known after routing
+			if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+				span.SetName(r.Method + " " + rctx.RoutePattern())
+				span.SetAttributes(attribute.String("http.route", rctx.RoutePattern()))
+			}
+			span.SetAttributes(attribute.Int("http.response.status_code", ww.Status()))
+			if ww.Status() >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(ww.Status()))
+			}
+		}()
+
+		h.ServeHTTP(ww, r.WithContext(ctx))
+	}
+	return http.HandlerFunc(fn)
+}
@@ -17,11 +17,11 @@ func NewUserHandler(r chi.Router, svc user.Service) *UserHandler {
 	h := &UserHandler{
 		svc: svc,
 	}
-	r.Post("/users", h.Create)
-	r.Get("/users", h.List)
-	r.Get("/users/{id}", h.Get)
-	r.Put("/users/{id}", h.Update)
-	r.Delete("/users/{id}", h.Delete)
+	r.With(RequireScope(user.ScopeUsersWrite)).Post("/users", h.Create)
+	r.With(RequireScope(user.ScopeUsersRead, user.ScopeUsersReadPublic)).Get("/users", h.List)
+	r.With(RequireScope(user.ScopeUsersRead, user.ScopeUsersReadPublic)).Get("/users/{id}", h.Get)
+	r.With(RequireScope(user.ScopeUsersWrite)).Put("/users/{id}", h.Update)
+	r.With(RequireScope(user.ScopeUsersDelete)).Delete("/users/{id}", h.Delete)
 	return h
 }
 
@@ -47,7 +47,7 @@ func (h UserHandler) Create(w http.ResponseWriter, req *http.Request) {
 		respondWithError(w, err)
 		return
 	}
-	respondCreated(w, u)
+	respondCreated(w, projectUser(projection(ctx), u))
 }
 
 func (h UserHandler) List(w http.ResponseWriter, req *http.Request) {
@@ -74,7 +74,7 @@ func (h UserHandler) List(w http.ResponseWriter, req *http.Request) {
 		respondWithError(w, err)
 		return
 	}
-	respondOK(w, resp)
+	respondOK(w, projectList(projection(req.Context()), resp))
 }
 
 func (h UserHandler) Get(w http.ResponseWriter, req *http.Request) {
@@ -92,7 +92,7 @@ func (h UserHandler) Get(w http.ResponseWriter, req *http.Request) {
 	// Then I could check If-None-Match and return the user only if changed
 	// otherwise http.StatusNotModified
 
-	respondOK(w, u)
+	respondOK(w, projectUser(projection(req.Context()), u))
 }
 
 func (h UserHandler) Update(w http.ResponseWriter, req *http.Request) {
@@ -118,7 +118,7 @@ func (h UserHandler) Update(w http.ResponseWriter, req *http.Request) {
 		respondWithError(w, err)
 		return
 	}
-	respondOK(w, u)
+	respondOK(w, projectUser(projection(ctx), u))
 }
 
 func (h UserHandler) Delete(w http.ResponseWriter, req *http.Request) {
@@ -0,0 +1,38 @@
+// Package idempotency keeps the responses of requests by idempotency key, so
+// retries of a request are answered with the first response instead of
+// running it again. Records are kept in a pluggable Store so retries can
+// reach any instance.
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Response is the response of a request.
+type Response struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Record is what is kept of a request by its idempotency key.
+type Record struct {
+	// Fingerprint identifies the payload of the request, retries with a
+	// different payload are rejected.
+	Fingerprint string `json:"fingerprint"`
+	// Response is nil while the request is in progress.
+	Response *Response `json:"response,omitempty"`
+}
+
+// Store keeps the records by idempotency key.
+type Store interface {
+	// Reserve keeps a record with fingerprint for ttl if there's none of key,
+	// in which case it returns nil. Otherwise it returns the existing record.
+	Reserve(_ context.Context, key, fingerprint string, ttl time.Duration) (*Record, error)
+	// Complete stores the response of the request which reserved key,
+	// keeping it for ttl.
+	Complete(_ context.Context, key string, resp *Response, ttl time.Duration) error
+	// Release removes the record of key, so the request can be retried.
+	Release(_ context.Context, key string) error
+}
@@ -0,0 +1,59 @@
+package idempotency_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guilherme-santos/user/idempotency"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStores(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	stores := map[string]idempotency.Store{
+		"memory": idempotency.NewMemoryStore(),
+		"redis":  idempotency.NewRedisStore(client, "idempotency:"),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			rec, err := store.Reserve(ctx, "key", "fp1", time.Hour)
+			assert.NoError(t, err)
+			assert.Nil(t, rec)
+
+			// in progress
+			rec, err = store.Reserve(ctx, "key", "fp2", time.Hour)
+			assert.NoError(t, err)
+			assert.Equal(t, &idempotency.Record{Fingerprint: "fp1"}, rec)
+
+			resp := &idempotency.Response{Status: 201, ContentType: "application/json", Body: []byte(`{"id":"1"}`)}
+			err = store.Complete(ctx, "key", resp, time.Hour)
+			assert.NoError(t, err)
+
+			rec, err = store.Reserve(ctx, "key", "fp1", time.Hour)
+			assert.NoError(t, err)
+			assert.Equal(t, &idempotency.Record{Fingerprint: "fp1", Response: resp}, rec)
+
+			// released keys can be reserved again
+			err = store.Release(ctx, "key")
+			assert.NoError(t, err)
+			rec, err = store.Reserve(ctx, "key", "fp2", time.Hour)
+			assert.NoError(t, err)
+			assert.Nil(t, rec)
+
+			// other keys have their own record
+			rec, err = store.Reserve(ctx, "other", "fp1", time.Hour)
+			assert.NoError(t, err)
+			assert.Nil(t, rec)
+		})
+	}
+}
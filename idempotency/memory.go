@@ -0,0 +1,86 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often expired records are removed from MemoryStore.
+const sweepInterval = time.Minute
+
+// MemoryStore keeps the records in memory, so retries must reach the same
+// instance.
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]*entry
+	lastSweep time.Time
+}
+
+type entry struct {
+	record    Record
+	expiresAt time.Time
+}
+
+// Make sure MemoryStore implements Store
+var _ Store = &MemoryStore{}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records:   make(map[string]*entry),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryStore) Reserve(_ context.Context, key, fingerprint string, ttl time.Duration) (*Record, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) > sweepInterval {
+		s.sweep(now)
+	}
+
+	e, ok := s.records[key]
+	if ok && now.Before(e.expiresAt) {
+		rec := e.record
+		return &rec, nil
+	}
+	s.records[key] = &entry{
+		record:    Record{Fingerprint: fingerprint},
+		expiresAt: now.Add(ttl),
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, key string, resp *Response, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.records[key]
+	if !ok {
+		return nil
+	}
+	e.record.Response = resp
+	e.expiresAt = time.Now().Add(ttl)
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+// sweep removes the expired records.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, e := range s.records {
+		if !now.Before(e.expiresAt) {
+			delete(s.records, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,85 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore keeps the records in redis, or any redis compatible server, so
+// retries can reach any instance.
+type RedisStore struct {
+	client redis.Cmdable
+	prefix string
+}
+
+// Make sure RedisStore implements Store
+var _ Store = &RedisStore{}
+
+// NewRedisStore returns a store which prefixes all keys with prefix.
+func NewRedisStore(client redis.Cmdable, prefix string) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s RedisStore) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Record, error) {
+	value, err := json.Marshal(Record{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+	// the record may expire between SETNX and GET, so it's tried again
+	for {
+		ok, err := s.client.SetNX(ctx, s.prefix+key, value, ttl).Result()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return nil, nil
+		}
+
+		data, err := s.client.Get(ctx, s.prefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var rec Record
+		err = json.Unmarshal(data, &rec)
+		if err != nil {
+			return nil, err
+		}
+		return &rec, nil
+	}
+}
+
+func (s RedisStore) Complete(ctx context.Context, key string, resp *Response, ttl time.Duration) error {
+	data, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var rec Record
+	err = json.Unmarshal(data, &rec)
+	if err != nil {
+		return err
+	}
+
+	rec.Response = resp
+	value, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return s.client.SetXX(ctx, s.prefix+key, value, ttl).Err()
+}
+
+func (s RedisStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.prefix+key).Err()
+}
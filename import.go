@@ -0,0 +1,247 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrBatchNotSupported = &Err{Type: Unknown, Code: "batch_not_supported", Message: "storage doesn't support batch creation"}
+	ErrImportAborted     = &Err{Type: InvalidArgument, Code: "import_aborted", Message: "user not imported because other rows failed"}
+	ErrImportTooLarge    = &Err{Type: InvalidArgument, Code: "import_too_large", Message: "Too many rows to import all or nothing, split the import or use partial mode"}
+)
+
+//go:generate mockgen -package mock -mock_names ImportService=ImportService -destination mock/importsvc.go github.com/guilherme-santos/user ImportService
+
+// ImportService is an interface which implements the bulk import of users.
+type ImportService interface {
+	// Import reads all users from r and creates them, fn is called with the
+	// outcome of each row as soon as it's known.
+	Import(_ context.Context, r ImportReader, opts *ImportOptions, fn func(*ImportResult)) (*ImportSummary, error)
+}
+
+//go:generate mockgen -package mock -mock_names BatchStorage=BatchStorage -destination mock/batchstorage.go github.com/guilherme-santos/user BatchStorage
+
+// BatchStorage is implemented by storages which are able to create many users
+// within the same transaction.
+type BatchStorage interface {
+	// CreateBatch creates all users returning one error (or nil) per user.
+	// If atomic is true and any user fails nothing is persisted. Unless
+	// entries is nil, the entry of each user created is appended to the
+	// audit log in the same transaction, its UserID is set to the new id.
+	CreateBatch(_ context.Context, users []*User, entries []*AuditEntry, atomic bool) ([]error, error)
+}
+
+// ImportReader reads the users to be imported, it returns io.EOF when there's
+// no more users. A *Error returned is reported as failure of the row, any other
+// error aborts the import.
+type ImportReader interface {
+	Read() (*User, error)
+}
+
+// ImportOptions contains the options used when importing users.
+type ImportOptions struct {
+	// DryRun only validates the users, nothing is persisted.
+	DryRun bool
+	// PreHashed allows users to be imported with a bcrypt hash instead of the plain password.
+	PreHashed bool
+	// AllOrNothing persists the users only if all of them succeed.
+	AllOrNothing bool
+	// BatchSize is the amount of users created in the same transaction.
+	BatchSize int
+	// AllOrNothingLimit is the maximum amount of users imported with
+	// AllOrNothing, as all of them are kept in memory and created in the
+	// same transaction. Their plain passwords are all hashed before it
+	// starts, about limit / GOMAXPROCS seconds at the default limit of 1000.
+	AllOrNothingLimit int
+}
+
+func NewImportOptions() *ImportOptions {
+	return &ImportOptions{
+		BatchSize:         100,
+		AllOrNothingLimit: 1000,
+	}
+}
+
+// ImportResult is the outcome of a single row, row starts from 1.
+type ImportResult struct {
+	Row   int
+	ID    string
+	Error error
+}
+
+// ImportSummary contains the totals of an import.
+type ImportSummary struct {
+	Total   int  `json:"total"`
+	Created int  `json:"created"`
+	Failed  int  `json:"failed"`
+	DryRun  bool `json:"dry_run"`
+}
+
+// Make sure ServiceImpl implements ImportService
+var _ ImportService = &ServiceImpl{}
+
+// Import validates and creates users in batches, recording in the audit log
+// (within the transaction of the batch) and publishing a user.created event for
+// each one of them.
+func (s ServiceImpl) Import(ctx context.Context, r ImportReader, opts *ImportOptions, fn func(*ImportResult)) (*ImportSummary, error) {
+	if opts == nil {
+		opts = NewImportOptions()
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = NewImportOptions().BatchSize
+	}
+	if opts.AllOrNothingLimit <= 0 {
+		opts.AllOrNothingLimit = NewImportOptions().AllOrNothingLimit
+	}
+
+	bs, ok := s.storage.(BatchStorage)
+	if !ok && !opts.DryRun {
+		return nil, ErrBatchNotSupported
+	}
+
+	summary := &ImportSummary{DryRun: opts.DryRun}
+	report := func(res *ImportResult) {
+		if res.Error != nil {
+			summary.Failed++
+		} else if res.ID != "" {
+			summary.Created++
+		}
+		fn(res)
+	}
+
+	var (
+		users   []*User
+		entries []*AuditEntry
+		rows    []int
+	)
+
+	create := func() error {
+		if len(users) == 0 {
+			return nil
+		}
+		errs, err := bs.CreateBatch(ctx, users, entries, opts.AllOrNothing)
+		if err != nil {
+			return err
+		}
+		var failed bool
+		for _, err := range errs {
+			if err != nil {
+				failed = true
+				break
+			}
+		}
+		for i, u := range users {
+			res := &ImportResult{Row: rows[i]}
+			switch {
+			case errs[i] != nil:
+				res.Error = errs[i]
+			case failed && opts.AllOrNothing:
+				res.Error = ErrImportAborted
+			default:
+				res.ID = u.ID
+				s.imported(ctx, u)
+			}
+			report(res)
+		}
+		users, entries, rows = users[:0], entries[:0], rows[:0]
+		return nil
+	}
+
+	for {
+		u, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		summary.Total++
+		row := summary.Total
+
+		if err == nil {
+			err = validateImport(u, opts)
+		}
+		if err != nil {
+			var uerr *Error
+			if !errors.As(err, &uerr) {
+				return nil, err
+			}
+			report(&ImportResult{Row: row, Error: err})
+			continue
+		}
+		if opts.DryRun {
+			report(&ImportResult{Row: row})
+			continue
+		}
+
+		if opts.AllOrNothing && len(users) >= opts.AllOrNothingLimit {
+			// nothing was persisted yet
+			return nil, ErrImportTooLarge
+		}
+		users = append(users, u)
+		entries = append(entries, NewAuditEntry(ctx, AuditCreated, nil, u))
+		rows = append(rows, row)
+		// When it's all or nothing every user need to be in the same transaction.
+		if !opts.AllOrNothing && len(users) >= opts.BatchSize {
+			err = create()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if opts.AllOrNothing && summary.Failed > 0 {
+		for _, row := range rows {
+			report(&ImportResult{Row: row, Error: ErrImportAborted})
+		}
+		return summary, nil
+	}
+	err := create()
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// imported publishes the user.created event of the imported user, as the
+// user is already persisted errors are only logged.
+func (s ServiceImpl) imported(ctx context.Context, u *User) {
+	ctx = withUserID(ctx, u.ID)
+	err := s.eventsvc.UserCreated(ctx, u)
+	if err != nil {
+		Logger(ctx).
+			WithField("event", "user.created").
+			WithError(err).
+			Error("unable to publish event of imported user")
+	}
+}
+
+func validateImport(u *User, opts *ImportOptions) error {
+	// IDs are always generated by the storage
+	u.ID = ""
+	if u.PasswordHash != "" {
+		if !opts.PreHashed {
+			return &FieldError{
+				Err: Error{
+					Type:    InvalidArgument,
+					Code:    "password_hash_not_allowed",
+					Message: "Pre-hashed passwords are not allowed without pre_hashed option",
+				},
+				Field: "password_hash",
+			}
+		}
+		_, err := bcrypt.Cost([]byte(u.PasswordHash))
+		if err != nil {
+			return &FieldError{
+				Err: Error{
+					Type:    InvalidArgument,
+					Code:    "invalid_password_hash",
+					Message: "Provided password hash is not a valid bcrypt hash",
+				},
+				Field: "password_hash",
+			}
+		}
+	}
+	return u.Validate()
+}
@@ -0,0 +1,166 @@
+package user_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// batchStorage combines both mocks as the service expects a single storage.
+type batchStorage struct {
+	*mock.UserStorage
+	*mock.BatchStorage
+}
+
+type sliceImportReader []*user.User
+
+func (r *sliceImportReader) Read() (*user.User, error) {
+	if len(*r) == 0 {
+		return nil, io.EOF
+	}
+	u := (*r)[0]
+	*r = (*r)[1:]
+	return u, nil
+}
+
+func TestUserServiceImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	u1 := newUser()
+	u2 := newUser()
+	u2.Email = "invalid"
+	u3 := newUser()
+	u3.Email = "other@gmail.com"
+
+	// Creates only the valid users
+	storage := mock.NewBatchStorage(ctrl)
+	storage.EXPECT().
+		CreateBatch(gomock.Any(), []*user.User{u1, u3}, gomock.Any(), false).
+		DoAndReturn(func(_ context.Context, users []*user.User, entries []*user.AuditEntry, _ bool) ([]error, error) {
+			// Records each user in the audit log within the batch
+			if assert.Len(t, entries, 2) {
+				assert.Equal(t, user.AuditCreated, entries[0].Action)
+				assert.Equal(t, user.AuditCreated, entries[1].Action)
+			}
+			users[0].ID = "uuid-1"
+			users[1].ID = "uuid-3"
+			return []error{nil, nil}, nil
+		})
+
+	// Publish a user.created event per user
+	eventsvc := mock.NewEventService(ctrl)
+	eventsvc.EXPECT().UserCreated(gomock.Any(), u1).Return(nil)
+	eventsvc.EXPECT().UserCreated(gomock.Any(), u3).Return(nil)
+
+	audit := mock.NewAuditStorage(ctrl)
+
+	var results []*user.ImportResult
+	r := &sliceImportReader{u1, u2, u3}
+
+	svc := user.NewService(batchStorage{BatchStorage: storage}, nil, eventsvc, audit)
+	summary, err := svc.Import(ctx, r, nil, func(res *user.ImportResult) {
+		results = append(results, res)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &user.ImportSummary{Total: 3, Created: 2, Failed: 1}, summary)
+	if assert.Len(t, results, 3) {
+		assert.Equal(t, 2, results[0].Row)
+		assert.EqualError(t, results[0].Error, "code=invalid_email field=email message=Provided email doesn't seems to be valid")
+		assert.Equal(t, &user.ImportResult{Row: 1, ID: "uuid-1"}, results[1])
+		assert.Equal(t, &user.ImportResult{Row: 3, ID: "uuid-3"}, results[2])
+	}
+}
+
+func TestUserServiceImportAllOrNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	u1 := newUser()
+	u2 := newUser()
+	u2.Country = ""
+
+	// Nothing is created or published as one user is invalid
+	storage := mock.NewBatchStorage(ctrl)
+	eventsvc := mock.NewEventService(ctrl)
+	audit := mock.NewAuditStorage(ctrl)
+
+	var results []*user.ImportResult
+	r := &sliceImportReader{u1, u2}
+	opts := user.NewImportOptions()
+	opts.AllOrNothing = true
+
+	svc := user.NewService(batchStorage{BatchStorage: storage}, nil, eventsvc, audit)
+	summary, err := svc.Import(ctx, r, opts, func(res *user.ImportResult) {
+		results = append(results, res)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &user.ImportSummary{Total: 2, Failed: 2}, summary)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, 2, results[0].Row)
+		assert.Equal(t, &user.ImportResult{Row: 1, Error: user.ErrImportAborted}, results[1])
+	}
+}
+
+func TestUserServiceImportPreHashed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	u1 := newUser()
+	u1.Password = ""
+	u1.PasswordHash = "$2a$14$ajq8Q7fbtFRQvXpdCq7Jcuy.Rx1h/L4J60Otx.gyNLbAYctGMJ9tK"
+	u2 := newUser()
+	u2.Password = ""
+	u2.PasswordHash = "not-a-hash"
+
+	storage := mock.NewBatchStorage(ctrl)
+	eventsvc := mock.NewEventService(ctrl)
+	audit := mock.NewAuditStorage(ctrl)
+
+	var results []*user.ImportResult
+	r := &sliceImportReader{u1, u2}
+	opts := user.NewImportOptions()
+	opts.DryRun = true
+	opts.PreHashed = true
+
+	svc := user.NewService(batchStorage{BatchStorage: storage}, nil, eventsvc, audit)
+	summary, err := svc.Import(ctx, r, opts, func(res *user.ImportResult) {
+		results = append(results, res)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &user.ImportSummary{Total: 2, Failed: 1, DryRun: true}, summary)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, &user.ImportResult{Row: 1}, results[0])
+		assert.EqualError(t, results[1].Error, "code=invalid_password_hash field=password_hash message=Provided password hash is not a valid bcrypt hash")
+	}
+}
+
+func TestUserServiceImportAllOrNothingLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Nothing is created as the users don't fit in a single transaction
+	storage := mock.NewBatchStorage(ctrl)
+	eventsvc := mock.NewEventService(ctrl)
+	audit := mock.NewAuditStorage(ctrl)
+
+	r := &sliceImportReader{newUser(), newUser(), newUser()}
+	opts := user.NewImportOptions()
+	opts.AllOrNothing = true
+	opts.AllOrNothingLimit = 2
+
+	svc := user.NewService(batchStorage{BatchStorage: storage}, nil, eventsvc, audit)
+	_, err := svc.Import(context.Background(), r, opts, func(res *user.ImportResult) {
+		t.Errorf("unexpected result of row %d", res.Row)
+	})
+	assert.Equal(t, user.ErrImportTooLarge, err)
+}
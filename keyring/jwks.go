@@ -0,0 +1,41 @@
+package keyring
+
+import (
+	"encoding/base64"
+	"math/big"
+)
+
+// JWKS is the JSON Web Key Set with the public keys, as in RFC 7517.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is a RSA public key.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+}
+
+// JWKS returns the public keys of all keys in the keyring.
+func (kr *Keyring) JWKS() *JWKS {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	set := &JWKS{Keys: make([]JWK, len(kr.keys))}
+	for i, k := range kr.keys {
+		pub := k.private.PublicKey
+		set.Keys[i] = JWK{
+			KeyType:   "RSA",
+			KeyID:     k.id,
+			Use:       "sig",
+			Algorithm: "RS256",
+			N:         base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}
+	}
+	return set
+}
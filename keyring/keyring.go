@@ -0,0 +1,290 @@
+// Package keyring keeps the RSA keys used to sign the access tokens in a local
+// directory, one PEM file per key named after its id. The newest key signs new
+// tokens while the older ones are still published so the tokens signed by them
+// can be verified until they expire.
+package keyring
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guilherme-santos/user"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/xid"
+)
+
+const keySize = 2048
+
+// reloadInterval limits how often unknown kids reload the keys.
+const reloadInterval = time.Minute
+
+var (
+	ErrUnknownKey = errors.New("unknown signing key")
+	ErrNoKeys     = errors.New("no signing key")
+)
+
+type Keyring struct {
+	dir string
+
+	mu sync.RWMutex
+	// keys are sorted from the newest to the oldest
+	keys       []*key
+	reloadedAt time.Time
+}
+
+// claims are the claims of the tokens, tid is the tenant of the user.
+type claims struct {
+	jwt.RegisteredClaims
+	TenantID string `json:"tid,omitempty"`
+}
+
+type key struct {
+	id        string
+	private   *rsa.PrivateKey
+	createdAt time.Time
+}
+
+// Make sure Keyring implements user.TokenSigner
+var _ user.TokenSigner = &Keyring{}
+
+// Open loads all keys in dir, a new key is created when there's none.
+func Open(dir string) (*Keyring, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	kr := &Keyring{dir: dir}
+	err = kr.Reload()
+	if err == ErrNoKeys {
+		err = kr.Rotate()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return kr, nil
+}
+
+// Reload reads the keys from dir again, e.g. after another instance rotated
+// them. ErrNoKeys is returned when dir has none, the keys loaded before are
+// kept then.
+func (kr *Keyring) Reload() error {
+	files, err := filepath.Glob(filepath.Join(kr.dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return ErrNoKeys
+	}
+
+	keys := make([]*key, 0, len(files))
+	for _, file := range files {
+		k, err := readKey(file)
+		if err != nil {
+			return fmt.Errorf("unable to read key %s: %w", file, err)
+		}
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].createdAt.After(keys[j].createdAt)
+	})
+
+	kr.mu.Lock()
+	kr.keys = keys
+	kr.reloadedAt = time.Now()
+	kr.mu.Unlock()
+	return nil
+}
+
+func readKey(file string) (*key, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("invalid pem")
+	}
+	private, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	id := strings.TrimSuffix(filepath.Base(file), ".pem")
+	// ids are xids, which contain the creation time
+	xi, err := xid.FromString(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key id: %w", err)
+	}
+	return &key{id: id, private: private, createdAt: xi.Time()}, nil
+}
+
+// Rotate creates a new key, which signs all new tokens.
+func (kr *Keyring) Rotate() error {
+	private, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return err
+	}
+	id := xid.New()
+	block := &pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(private),
+	}
+	err = os.WriteFile(filepath.Join(kr.dir, id.String()+".pem"), pem.EncodeToMemory(block), 0600)
+	if err != nil {
+		return err
+	}
+
+	kr.mu.Lock()
+	kr.keys = append([]*key{{id: id.String(), private: private, createdAt: id.Time()}}, kr.keys...)
+	kr.mu.Unlock()
+	return nil
+}
+
+// Prune removes the keys replaced by a newer one longer than retain ago,
+// retain must be longer than the lifetime of the tokens.
+func (kr *Keyring) Prune(retain time.Duration) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	for i := len(kr.keys) - 1; i > 0; i-- {
+		// keys[i-1] replaced keys[i]
+		if time.Since(kr.keys[i-1].createdAt) < retain {
+			break
+		}
+		err := os.Remove(filepath.Join(kr.dir, kr.keys[i].id+".pem"))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		kr.keys = kr.keys[:i]
+	}
+	return nil
+}
+
+// RotateEvery rotates the keys when the current one is older than period,
+// pruning the ones replaced longer than retain ago, until ctx is done.
+func (kr *Keyring) RotateEvery(ctx context.Context, period, retain time.Duration) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		k, err := kr.current()
+		if err != nil || time.Since(k.createdAt) >= period {
+			err := kr.Rotate()
+			if err != nil {
+				user.Logger(ctx).WithError(err).Error("unable to rotate signing key")
+			}
+		}
+		err = kr.Prune(retain)
+		if err != nil {
+			user.Logger(ctx).WithError(err).Error("unable to prune signing keys")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sign signs claims with the newest key using RS256.
+func (kr *Keyring) Sign(tc *user.TokenClaims) (string, error) {
+	k, err := kr.current()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tc.ID,
+			Issuer:    tc.Issuer,
+			Subject:   tc.Subject,
+			IssuedAt:  jwt.NewNumericDate(tc.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(tc.ExpiresAt),
+		},
+		TenantID: tc.TenantID,
+	})
+	token.Header["kid"] = k.id
+	return token.SignedString(k.private)
+}
+
+// Verify verifies token with the key of its kid header, keys are reloaded
+// when it's unknown, at most once every reloadInterval.
+func (kr *Keyring) Verify(token string) (*user.TokenClaims, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(token, &c, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		k := kr.key(kid)
+		if k == nil && kr.canReload() {
+			err := kr.Reload()
+			if err != nil {
+				return nil, err
+			}
+			k = kr.key(kid)
+		}
+		if k == nil {
+			return nil, ErrUnknownKey
+		}
+		return &k.private.PublicKey, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tc := &user.TokenClaims{
+		ID:       c.ID,
+		Issuer:   c.Issuer,
+		Subject:  c.Subject,
+		TenantID: c.TenantID,
+	}
+	if c.IssuedAt != nil {
+		tc.IssuedAt = c.IssuedAt.Time
+	}
+	if c.ExpiresAt != nil {
+		tc.ExpiresAt = c.ExpiresAt.Time
+	}
+	return tc, nil
+}
+
+// current returns the newest key, which signs new tokens.
+func (kr *Keyring) current() (*key, error) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	if len(kr.keys) == 0 {
+		return nil, ErrNoKeys
+	}
+	return kr.keys[0], nil
+}
+
+func (kr *Keyring) canReload() bool {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	return time.Since(kr.reloadedAt) >= reloadInterval
+}
+
+func (kr *Keyring) key(id string) *key {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	for _, k := range kr.keys {
+		if k.id == id {
+			return k
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package keyring_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/keyring"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func claims(ttl time.Duration) *user.TokenClaims {
+	now := time.Now().Truncate(time.Second)
+	return &user.TokenClaims{
+		ID:        "token-1",
+		Issuer:    "usersvc",
+		Subject:   "user-1",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(ttl),
+		TenantID:  "brand-1",
+	}
+}
+
+func TestKeyringSignVerify(t *testing.T) {
+	kr, err := keyring.Open(t.TempDir())
+	require.NoError(t, err)
+
+	c := claims(time.Minute)
+	token, err := kr.Sign(c)
+	require.NoError(t, err)
+
+	got, err := kr.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, c.Subject, got.Subject)
+	assert.Equal(t, c.TenantID, got.TenantID)
+	assert.Equal(t, c.ExpiresAt.Unix(), got.ExpiresAt.Unix())
+
+	// Expired tokens are rejected
+	token, err = kr.Sign(claims(-time.Minute))
+	require.NoError(t, err)
+	_, err = kr.Verify(token)
+	assert.Error(t, err)
+
+	// Tampered tokens are rejected
+	token, err = kr.Sign(c)
+	require.NoError(t, err)
+	_, err = kr.Verify(token[:len(token)-2] + "xx")
+	assert.Error(t, err)
+}
+
+func TestKeyringRotate(t *testing.T) {
+	dir := t.TempDir()
+	kr, err := keyring.Open(dir)
+	require.NoError(t, err)
+
+	old, err := kr.Sign(claims(time.Minute))
+	require.NoError(t, err)
+
+	require.NoError(t, kr.Rotate())
+	assert.Len(t, kr.JWKS().Keys, 2)
+
+	// Tokens signed by the old key are still valid
+	_, err = kr.Verify(old)
+	assert.NoError(t, err)
+
+	// Other instances sharing dir load the same keys
+	other, err := keyring.Open(dir)
+	require.NoError(t, err)
+	token, err := kr.Sign(claims(time.Minute))
+	require.NoError(t, err)
+	_, err = other.Verify(token)
+	assert.NoError(t, err)
+
+	// Prune removes the replaced key
+	require.NoError(t, kr.Prune(0))
+	assert.Len(t, kr.JWKS().Keys, 1)
+	files, _ := filepath.Glob(filepath.Join(dir, "*.pem"))
+	assert.Len(t, files, 1)
+	_, err = kr.Verify(old)
+	assert.Error(t, err)
+}
+
+func TestKeyringReloadWithoutKeys(t *testing.T) {
+	dir := t.TempDir()
+	kr, err := keyring.Open(dir)
+	require.NoError(t, err)
+
+	// the keys of an emptied dir aren't reloaded, they still sign tokens
+	files, _ := filepath.Glob(filepath.Join(dir, "*.pem"))
+	for _, f := range files {
+		require.NoError(t, os.Remove(f))
+	}
+	assert.Equal(t, keyring.ErrNoKeys, kr.Reload())
+	_, err = kr.Sign(claims(time.Minute))
+	assert.NoError(t, err)
+}
+
+func TestKeyringJWKS(t *testing.T) {
+	dir := t.TempDir()
+	kr, err := keyring.Open(dir)
+	require.NoError(t, err)
+
+	set := kr.JWKS()
+	require.Len(t, set.Keys, 1)
+	k := set.Keys[0]
+	assert.Equal(t, "RSA", k.KeyType)
+	assert.Equal(t, "RS256", k.Algorithm)
+	assert.Equal(t, "sig", k.Use)
+	assert.Equal(t, "AQAB", k.E)
+	assert.NotEmpty(t, k.N)
+
+	// kid is the name of the key file
+	_, err = os.Stat(filepath.Join(dir, k.KeyID+".pem"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,96 @@
+// Package lifecycle shuts the service down in order once it's asked to stop,
+// e.g. by SIGTERM: readiness fails first so load balancers stop sending new
+// requests, then after a drain period each step runs in the order they were
+// registered, sharing a single deadline.
+package lifecycle
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Manager struct {
+	log     logrus.FieldLogger
+	drain   time.Duration
+	timeout time.Duration
+
+	draining atomic.Bool
+	steps    []step
+}
+
+type step struct {
+	name string
+	fn   func(context.Context) error
+}
+
+// New returns a Manager which waits drain after failing readiness and gives
+// timeout to all steps to finish.
+func New(log logrus.FieldLogger, drain, timeout time.Duration) *Manager {
+	return &Manager{
+		log:     log,
+		drain:   drain,
+		timeout: timeout,
+	}
+}
+
+// Ready returns false once the shutdown started.
+func (m *Manager) Ready() bool {
+	return !m.draining.Load()
+}
+
+// OnShutdown registers fn to run on shutdown after the steps registered
+// before it, fn should return when ctx is done.
+func (m *Manager) OnShutdown(name string, fn func(context.Context) error) {
+	m.steps = append(m.steps, step{name: name, fn: fn})
+}
+
+// Wait blocks until SIGTERM or SIGINT is received or errCh reports a failure,
+// then it shuts down. The failure is returned after the shutdown, so the
+// process doesn't exit successfully, it's nil when a signal was received.
+func (m *Manager) Wait(errCh <-chan error) error {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, os.Interrupt)
+	defer signal.Stop(sigCh)
+
+	var err error
+	select {
+	case err = <-errCh:
+		m.log.WithError(err).Error("unable to run server, shutting down")
+	case sig := <-sigCh:
+		m.log.WithField("signal", sig).Warn("signal received, shutting down")
+	}
+	m.Shutdown()
+	return err
+}
+
+// Shutdown fails readiness, waits the drain period and runs the steps in
+// order. Failures are logged and the next steps still run.
+func (m *Manager) Shutdown() {
+	m.draining.Store(true)
+	if m.drain > 0 {
+		m.log.WithField("drain_period", m.drain.String()).Info("readiness failing, draining requests")
+		time.Sleep(m.drain)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	for _, s := range m.steps {
+		log := m.log.WithField("step", s.name)
+		t1 := time.Now()
+		err := s.fn(ctx)
+		log = log.WithField("elapsed", time.Since(t1).String())
+		if err != nil {
+			log.WithError(err).Error("unable to shut down")
+			continue
+		}
+		log.Info("shut down")
+	}
+	m.log.Info("shutdown completed")
+}
@@ -0,0 +1,60 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guilherme-santos/user/lifecycle"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerShutdown(t *testing.T) {
+	log, hook := test.NewNullLogger()
+	lc := lifecycle.New(log, 10*time.Millisecond, 50*time.Millisecond)
+	assert.True(t, lc.Ready())
+
+	var steps []string
+	lc.OnShutdown("http", func(context.Context) error {
+		// readiness fails before any step
+		assert.False(t, lc.Ready())
+		steps = append(steps, "http")
+		return nil
+	})
+	lc.OnShutdown("grpc", func(ctx context.Context) error {
+		steps = append(steps, "grpc")
+		// steps share the deadline
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	lc.OnShutdown("mysql", func(ctx context.Context) error {
+		steps = append(steps, "mysql")
+		return errors.New("already closed")
+	})
+
+	t1 := time.Now()
+	err := lc.Wait(closedErr())
+	assert.EqualError(t, err, "server failed")
+	assert.Less(t, time.Since(t1), time.Second)
+	assert.Equal(t, []string{"http", "grpc", "mysql"}, steps)
+
+	var failed []string
+	for _, e := range hook.AllEntries() {
+		if e.Level == logrus.ErrorLevel && e.Data["step"] != nil {
+			failed = append(failed, e.Data["step"].(string))
+		}
+	}
+	assert.Equal(t, []string{"grpc", "mysql"}, failed)
+}
+
+// closedErr returns a channel with an error, so Wait doesn't wait for a
+// signal.
+func closedErr() <-chan error {
+	errCh := make(chan error, 1)
+	errCh <- errors.New("server failed")
+	return errCh
+}
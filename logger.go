@@ -2,8 +2,10 @@ package user
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type contextKey string
@@ -14,10 +16,90 @@ func SetLogger(ctx context.Context, logger logrus.FieldLogger) context.Context {
 	return context.WithValue(ctx, loggerCtx, logger)
 }
 
+// Logger returns the logger stored in ctx, if ctx has a span its trace_id and
+// span_id are added as fields.
 func Logger(ctx context.Context) logrus.FieldLogger {
 	logger, _ := ctx.Value(loggerCtx).(logrus.FieldLogger)
 	if logger == nil {
 		logger = logrus.New()
 	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		logger = logger.WithFields(logrus.Fields{
+			"trace_id": sc.TraceID().String(),
+			"span_id":  sc.SpanID().String(),
+		})
+	}
 	return logger
 }
+
+// WithLogFields returns a copy of ctx whose logger carries fields, so every
+// entry logged down the call chain can be correlated.
+func WithLogFields(ctx context.Context, fields logrus.Fields) context.Context {
+	logger, _ := ctx.Value(loggerCtx).(logrus.FieldLogger)
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return SetLogger(ctx, logger.WithFields(fields))
+}
+
+// NewLogFormatter returns a text or json formatter which replaces the value of
+// redact fields by Redacted.
+func NewLogFormatter(format string, redact []string) (logrus.Formatter, error) {
+	var f logrus.Formatter
+
+	switch format {
+	case "", "text":
+		f = &logrus.TextFormatter{}
+	case "json":
+		f = &logrus.JSONFormatter{}
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+	if len(redact) == 0 {
+		return f, nil
+	}
+	return NewRedactFormatter(f, redact...), nil
+}
+
+// RedactFormatter is a logrus.Formatter which replaces the value of some
+// fields before calling the decorated formatter.
+type RedactFormatter struct {
+	formatter logrus.Formatter
+	fields    map[string]struct{}
+}
+
+func NewRedactFormatter(f logrus.Formatter, fields ...string) *RedactFormatter {
+	rf := &RedactFormatter{
+		formatter: f,
+		fields:    make(map[string]struct{}, len(fields)),
+	}
+	for _, field := range fields {
+		rf.fields[field] = struct{}{}
+	}
+	return rf
+}
+
+func (f RedactFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	var data logrus.Fields
+	for k, v := range entry.Data {
+		if _, ok := f.fields[k]; !ok {
+			continue
+		}
+		if data == nil {
+			// entry.Data may be shared with other entries, so it's copied
+			data = make(logrus.Fields, len(entry.Data))
+			for k, v := range entry.Data {
+				data[k] = v
+			}
+		}
+		if v != nil && v != "" {
+			data[k] = Redacted
+		}
+	}
+	if data == nil {
+		return f.formatter.Format(entry)
+	}
+	redacted := *entry
+	redacted.Data = data
+	return f.formatter.Format(&redacted)
+}
@@ -0,0 +1,41 @@
+package user_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogFormatterRedact(t *testing.T) {
+	formatter, err := user.NewLogFormatter("json", []string{"email"})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(formatter)
+
+	ctx := user.SetLogger(context.Background(), logger)
+	ctx = user.WithLogFields(ctx, logrus.Fields{"user_id": "uuid"})
+	entry := user.Logger(ctx).WithField("email", "xguiga@gmail.com")
+	entry.Info("user created")
+
+	var fields map[string]interface{}
+	err = json.Unmarshal(buf.Bytes(), &fields)
+	assert.NoError(t, err)
+	assert.Equal(t, "uuid", fields["user_id"])
+	assert.Equal(t, user.Redacted, fields["email"])
+	// the entry itself is not changed
+	assert.Equal(t, "xguiga@gmail.com", entry.Data["email"])
+}
+
+func TestNewLogFormatterUnknown(t *testing.T) {
+	_, err := user.NewLogFormatter("xml", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/guilherme-santos/user"
+)
+
+// EventService records the outcome of every event published by the decorated service.
+type EventService struct {
+	eventsvc user.EventService
+}
+
+// Make sure EventService implements user.EventService
+var _ user.EventService = &EventService{}
+
+func NewEventService(eventsvc user.EventService) *EventService {
+	return &EventService{
+		eventsvc: eventsvc,
+	}
+}
+
+func (s EventService) UserCreated(ctx context.Context, u *user.User) error {
+	return published("user.created", s.eventsvc.UserCreated(ctx, u))
+}
+
+func (s EventService) UserUpdated(ctx context.Context, u *user.User) error {
+	return published("user.updated", s.eventsvc.UserUpdated(ctx, u))
+}
+
+func (s EventService) UserDeleted(ctx context.Context, u *user.User) error {
+	return published("user.deleted", s.eventsvc.UserDeleted(ctx, u))
+}
+
+func (s EventService) RoleGranted(ctx context.Context, ra *user.RoleAssignment) error {
+	return published("role.granted", s.eventsvc.RoleGranted(ctx, ra))
+}
+
+func (s EventService) RoleRevoked(ctx context.Context, ra *user.RoleAssignment) error {
+	return published("role.revoked", s.eventsvc.RoleRevoked(ctx, ra))
+}
+
+func published(event string, err error) error {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	EventsPublished.WithLabelValues(event, outcome).Inc()
+	return err
+}
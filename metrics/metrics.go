@@ -0,0 +1,121 @@
+// Package metrics defines all prometheus metrics exposed by the service and
+// decorators which record them without changing the decorated behaviour.
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/guilherme-santos/user"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "usersvc"
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total of HTTP requests by route and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of HTTP requests by route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	ServiceDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "service",
+		Name:      "duration_seconds",
+		Help:      "Latency of user.Service methods.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	ServiceErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "service",
+		Name:      "errors_total",
+		Help:      "Total of errors returned by user.Service methods by user.Type.",
+	}, []string{"method", "type"})
+
+	StorageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "duration_seconds",
+		Help:      "Latency of user.Storage methods.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	StorageErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "errors_total",
+		Help:      "Total of errors returned by user.Storage methods by user.Type.",
+	}, []string{"method", "type"})
+
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Latency of SQL queries by caller method and operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"caller", "operation"})
+
+	DBSlowQueries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "slow_queries_total",
+		Help:      "Total of SQL queries above the slow query threshold by caller method.",
+	}, []string{"caller"})
+
+	PasswordHashDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "password",
+		Name:      "hash_duration_seconds",
+		Help:      "Time spent hashing passwords with bcrypt.",
+		Buckets:   []float64{.1, .25, .5, 1, 2, 4, 8},
+	})
+
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Total of cache lookups by result (hit or miss).",
+	}, []string{"result"})
+
+	EventsPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "events",
+		Name:      "published_total",
+		Help:      "Total of events published by outcome (success or error).",
+	}, []string{"event", "outcome"})
+)
+
+// Handler returns the handler which exposes all metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RegisterDBStats exposes sql.DBStats of db as gauges.
+func RegisterDBStats(db *sql.DB, name string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// ErrorType returns the label of the user.Type of err.
+func ErrorType(err error) string {
+	var uerr *user.Error
+	if errors.As(err, &uerr) {
+		return uerr.Type.String()
+	}
+	return user.Unknown.String()
+}
@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/guilherme-santos/user"
+)
+
+// UserStorage records latency and errors of every method of the decorated storage.
+type UserStorage struct {
+	storage user.Storage
+}
+
+// Make sure UserStorage implements all storages
+var (
+	_ user.Storage        = &UserStorage{}
+	_ user.AuditedStorage = &UserStorage{}
+	_ user.BatchStorage   = &UserStorage{}
+	_ user.ExportStorage  = &UserStorage{}
+)
+
+func NewUserStorage(storage user.Storage) *UserStorage {
+	return &UserStorage{
+		storage: storage,
+	}
+}
+
+func (s UserStorage) Create(ctx context.Context, u *user.User) (err error) {
+	defer observe(StorageDuration, StorageErrors, "Create", time.Now(), &err)
+	return s.storage.Create(ctx, u)
+}
+
+func (s UserStorage) Update(ctx context.Context, u *user.User) (err error) {
+	defer observe(StorageDuration, StorageErrors, "Update", time.Now(), &err)
+	return s.storage.Update(ctx, u)
+}
+
+func (s UserStorage) Delete(ctx context.Context, id string) (err error) {
+	defer observe(StorageDuration, StorageErrors, "Delete", time.Now(), &err)
+	return s.storage.Delete(ctx, id)
+}
+
+func (s UserStorage) Get(ctx context.Context, id string) (_ *user.User, err error) {
+	defer observe(StorageDuration, StorageErrors, "Get", time.Now(), &err)
+	return s.storage.Get(ctx, id)
+}
+
+func (s UserStorage) List(ctx context.Context, opts *user.ListOptions) (_ *user.ListResponse, err error) {
+	defer observe(StorageDuration, StorageErrors, "List", time.Now(), &err)
+	return s.storage.List(ctx, opts)
+}
+
+func (s UserStorage) CreateAudited(ctx context.Context, u *user.User, e *user.AuditEntry) (err error) {
+	defer observe(StorageDuration, StorageErrors, "CreateAudited", time.Now(), &err)
+	as, ok := s.storage.(user.AuditedStorage)
+	if !ok {
+		return user.ErrAuditNotSupported
+	}
+	return as.CreateAudited(ctx, u, e)
+}
+
+func (s UserStorage) UpdateAudited(ctx context.Context, u *user.User, e *user.AuditEntry) (err error) {
+	defer observe(StorageDuration, StorageErrors, "UpdateAudited", time.Now(), &err)
+	as, ok := s.storage.(user.AuditedStorage)
+	if !ok {
+		return user.ErrAuditNotSupported
+	}
+	return as.UpdateAudited(ctx, u, e)
+}
+
+func (s UserStorage) DeleteAudited(ctx context.Context, id string, e *user.AuditEntry) (err error) {
+	defer observe(StorageDuration, StorageErrors, "DeleteAudited", time.Now(), &err)
+	as, ok := s.storage.(user.AuditedStorage)
+	if !ok {
+		return user.ErrAuditNotSupported
+	}
+	return as.DeleteAudited(ctx, id, e)
+}
+
+func (s UserStorage) CreateBatch(ctx context.Context, users []*user.User, entries []*user.AuditEntry, atomic bool) (_ []error, err error) {
+	defer observe(StorageDuration, StorageErrors, "CreateBatch", time.Now(), &err)
+	bs, ok := s.storage.(user.BatchStorage)
+	if !ok {
+		return nil, user.ErrBatchNotSupported
+	}
+	return bs.CreateBatch(ctx, users, entries, atomic)
+}
+
+func (s UserStorage) Export(ctx context.Context, opts *user.ListOptions, fn func(*user.User) error) (err error) {
+	defer observe(StorageDuration, StorageErrors, "Export", time.Now(), &err)
+	es, ok := s.storage.(user.ExportStorage)
+	if !ok {
+		return user.ErrExportNotSupported
+	}
+	return es.Export(ctx, opts, fn)
+}
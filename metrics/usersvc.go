@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/guilherme-santos/user"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var errNotSupported = &user.Err{Type: user.Unknown, Code: "not_supported", Message: "operation not supported by the service"}
+
+// UserService records latency and errors of every method of the decorated service.
+type UserService struct {
+	svc user.Service
+}
+
+// Make sure UserService implements all services
+var (
+	_ user.Service       = &UserService{}
+	_ user.ImportService = &UserService{}
+	_ user.ExportService = &UserService{}
+	_ user.AuditService  = &UserService{}
+)
+
+func NewUserService(svc user.Service) *UserService {
+	return &UserService{
+		svc: svc,
+	}
+}
+
+func (s UserService) Create(ctx context.Context, u *user.User) (err error) {
+	defer observe(ServiceDuration, ServiceErrors, "Create", time.Now(), &err)
+	return s.svc.Create(ctx, u)
+}
+
+func (s UserService) Update(ctx context.Context, u *user.User) (err error) {
+	defer observe(ServiceDuration, ServiceErrors, "Update", time.Now(), &err)
+	return s.svc.Update(ctx, u)
+}
+
+func (s UserService) Delete(ctx context.Context, id string) (err error) {
+	defer observe(ServiceDuration, ServiceErrors, "Delete", time.Now(), &err)
+	return s.svc.Delete(ctx, id)
+}
+
+func (s UserService) Get(ctx context.Context, id string) (_ *user.User, err error) {
+	defer observe(ServiceDuration, ServiceErrors, "Get", time.Now(), &err)
+	return s.svc.Get(ctx, id)
+}
+
+func (s UserService) List(ctx context.Context, opts *user.ListOptions) (_ *user.ListResponse, err error) {
+	defer observe(ServiceDuration, ServiceErrors, "List", time.Now(), &err)
+	return s.svc.List(ctx, opts)
+}
+
+func (s UserService) Import(ctx context.Context, r user.ImportReader, opts *user.ImportOptions, fn func(*user.ImportResult)) (_ *user.ImportSummary, err error) {
+	defer observe(ServiceDuration, ServiceErrors, "Import", time.Now(), &err)
+	is, ok := s.svc.(user.ImportService)
+	if !ok {
+		return nil, errNotSupported
+	}
+	return is.Import(ctx, r, opts, fn)
+}
+
+func (s UserService) Export(ctx context.Context, opts *user.ListOptions, fn func(*user.User) error) (err error) {
+	defer observe(ServiceDuration, ServiceErrors, "Export", time.Now(), &err)
+	es, ok := s.svc.(user.ExportService)
+	if !ok {
+		return errNotSupported
+	}
+	return es.Export(ctx, opts, fn)
+}
+
+func (s UserService) AuditLog(ctx context.Context, userID string, opts *user.AuditListOptions) (_ *user.AuditListResponse, err error) {
+	defer observe(ServiceDuration, ServiceErrors, "AuditLog", time.Now(), &err)
+	as, ok := s.svc.(user.AuditService)
+	if !ok {
+		return nil, errNotSupported
+	}
+	return as.AuditLog(ctx, userID, opts)
+}
+
+// observe records the latency since start and err, if any, of method.
+func observe(duration *prometheus.HistogramVec, errors *prometheus.CounterVec, method string, start time.Time, err *error) {
+	duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if *err != nil {
+		errors.WithLabelValues(method, ErrorType(*err)).Inc()
+	}
+}
@@ -0,0 +1,53 @@
+package metrics_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/metrics"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserServiceErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	errors := metrics.ServiceErrors.WithLabelValues("Get", "not_found")
+	before := testutil.ToFloat64(errors)
+
+	svc := mock.NewUserService(ctrl)
+	svc.EXPECT().Get(gomock.Any(), "uuid").Return(nil, user.ErrNotFound)
+	svc.EXPECT().Get(gomock.Any(), "uuid-2").Return(&user.User{ID: "uuid-2"}, nil)
+
+	msvc := metrics.NewUserService(svc)
+	_, err := msvc.Get(ctx, "uuid")
+	assert.Equal(t, user.ErrNotFound, err)
+	_, err = msvc.Get(ctx, "uuid-2")
+	assert.NoError(t, err)
+
+	// only the failed call is counted as error
+	assert.Equal(t, before+1, testutil.ToFloat64(errors))
+}
+
+func TestEventServiceOutcome(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	u := &user.User{ID: "uuid"}
+	success := metrics.EventsPublished.WithLabelValues("user.created", "success")
+	before := testutil.ToFloat64(success)
+
+	eventsvc := mock.NewEventService(ctrl)
+	eventsvc.EXPECT().UserCreated(gomock.Any(), u).Return(nil)
+
+	err := metrics.NewEventService(eventsvc).UserCreated(ctx, u)
+	assert.NoError(t, err)
+	assert.Equal(t, before+1, testutil.ToFloat64(success))
+}
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/guilherme-santos/user (interfaces: APIKeyStorage)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	user "github.com/guilherme-santos/user"
+)
+
+// APIKeyStorage is a mock of APIKeyStorage interface.
+type APIKeyStorage struct {
+	ctrl     *gomock.Controller
+	recorder *APIKeyStorageMockRecorder
+}
+
+// APIKeyStorageMockRecorder is the mock recorder for APIKeyStorage.
+type APIKeyStorageMockRecorder struct {
+	mock *APIKeyStorage
+}
+
+// NewAPIKeyStorage creates a new mock instance.
+func NewAPIKeyStorage(ctrl *gomock.Controller) *APIKeyStorage {
+	mock := &APIKeyStorage{ctrl: ctrl}
+	mock.recorder = &APIKeyStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *APIKeyStorage) EXPECT() *APIKeyStorageMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *APIKeyStorage) Create(arg0 context.Context, arg1 *user.APIKey, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *APIKeyStorageMockRecorder) Create(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*APIKeyStorage)(nil).Create), arg0, arg1, arg2)
+}
+
+// GetByHash mocks base method.
+func (m *APIKeyStorage) GetByHash(arg0 context.Context, arg1 string) (*user.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHash", arg0, arg1)
+	ret0, _ := ret[0].(*user.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHash indicates an expected call of GetByHash.
+func (mr *APIKeyStorageMockRecorder) GetByHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHash", reflect.TypeOf((*APIKeyStorage)(nil).GetByHash), arg0, arg1)
+}
+
+// Revoke mocks base method.
+func (m *APIKeyStorage) Revoke(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *APIKeyStorageMockRecorder) Revoke(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*APIKeyStorage)(nil).Revoke), arg0, arg1)
+}
+
+// UpdateLastUsed mocks base method.
+func (m *APIKeyStorage) UpdateLastUsed(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastUsed", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastUsed indicates an expected call of UpdateLastUsed.
+func (mr *APIKeyStorageMockRecorder) UpdateLastUsed(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastUsed", reflect.TypeOf((*APIKeyStorage)(nil).UpdateLastUsed), arg0, arg1, arg2)
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/guilherme-santos/user (interfaces: APIKeyService)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	user "github.com/guilherme-santos/user"
+)
+
+// APIKeyService is a mock of APIKeyService interface.
+type APIKeyService struct {
+	ctrl     *gomock.Controller
+	recorder *APIKeyServiceMockRecorder
+}
+
+// APIKeyServiceMockRecorder is the mock recorder for APIKeyService.
+type APIKeyServiceMockRecorder struct {
+	mock *APIKeyService
+}
+
+// NewAPIKeyService creates a new mock instance.
+func NewAPIKeyService(ctrl *gomock.Controller) *APIKeyService {
+	mock := &APIKeyService{ctrl: ctrl}
+	mock.recorder = &APIKeyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *APIKeyService) EXPECT() *APIKeyServiceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *APIKeyService) Authenticate(arg0 context.Context, arg1 string) (*user.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", arg0, arg1)
+	ret0, _ := ret[0].(*user.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *APIKeyServiceMockRecorder) Authenticate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*APIKeyService)(nil).Authenticate), arg0, arg1)
+}
+
+// CreateAPIKey mocks base method.
+func (m *APIKeyService) CreateAPIKey(arg0 context.Context, arg1 *user.APIKey) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *APIKeyServiceMockRecorder) CreateAPIKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*APIKeyService)(nil).CreateAPIKey), arg0, arg1)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *APIKeyService) RevokeAPIKey(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *APIKeyServiceMockRecorder) RevokeAPIKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*APIKeyService)(nil).RevokeAPIKey), arg0, arg1)
+}
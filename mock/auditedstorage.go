@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/guilherme-santos/user (interfaces: AuditedStorage)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	user "github.com/guilherme-santos/user"
+)
+
+// AuditedStorage is a mock of AuditedStorage interface.
+type AuditedStorage struct {
+	ctrl     *gomock.Controller
+	recorder *AuditedStorageMockRecorder
+}
+
+// AuditedStorageMockRecorder is the mock recorder for AuditedStorage.
+type AuditedStorageMockRecorder struct {
+	mock *AuditedStorage
+}
+
+// NewAuditedStorage creates a new mock instance.
+func NewAuditedStorage(ctrl *gomock.Controller) *AuditedStorage {
+	mock := &AuditedStorage{ctrl: ctrl}
+	mock.recorder = &AuditedStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *AuditedStorage) EXPECT() *AuditedStorageMockRecorder {
+	return m.recorder
+}
+
+// CreateAudited mocks base method.
+func (m *AuditedStorage) CreateAudited(arg0 context.Context, arg1 *user.User, arg2 *user.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAudited", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAudited indicates an expected call of CreateAudited.
+func (mr *AuditedStorageMockRecorder) CreateAudited(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAudited", reflect.TypeOf((*AuditedStorage)(nil).CreateAudited), arg0, arg1, arg2)
+}
+
+// DeleteAudited mocks base method.
+func (m *AuditedStorage) DeleteAudited(arg0 context.Context, arg1 string, arg2 *user.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAudited", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAudited indicates an expected call of DeleteAudited.
+func (mr *AuditedStorageMockRecorder) DeleteAudited(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAudited", reflect.TypeOf((*AuditedStorage)(nil).DeleteAudited), arg0, arg1, arg2)
+}
+
+// UpdateAudited mocks base method.
+func (m *AuditedStorage) UpdateAudited(arg0 context.Context, arg1 *user.User, arg2 *user.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAudited", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAudited indicates an expected call of UpdateAudited.
+func (mr *AuditedStorageMockRecorder) UpdateAudited(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAudited", reflect.TypeOf((*AuditedStorage)(nil).UpdateAudited), arg0, arg1, arg2)
+}
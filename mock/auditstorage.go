@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/guilherme-santos/user (interfaces: AuditStorage)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	user "github.com/guilherme-santos/user"
+)
+
+// AuditStorage is a mock of AuditStorage interface.
+type AuditStorage struct {
+	ctrl     *gomock.Controller
+	recorder *AuditStorageMockRecorder
+}
+
+// AuditStorageMockRecorder is the mock recorder for AuditStorage.
+type AuditStorageMockRecorder struct {
+	mock *AuditStorage
+}
+
+// NewAuditStorage creates a new mock instance.
+func NewAuditStorage(ctrl *gomock.Controller) *AuditStorage {
+	mock := &AuditStorage{ctrl: ctrl}
+	mock.recorder = &AuditStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *AuditStorage) EXPECT() *AuditStorageMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *AuditStorage) Append(arg0 context.Context, arg1 *user.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Append indicates an expected call of Append.
+func (mr *AuditStorageMockRecorder) Append(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*AuditStorage)(nil).Append), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *AuditStorage) List(arg0 context.Context, arg1 string, arg2 *user.AuditListOptions) (*user.AuditListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*user.AuditListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *AuditStorageMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*AuditStorage)(nil).List), arg0, arg1, arg2)
+}
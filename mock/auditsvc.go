@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/guilherme-santos/user (interfaces: AuditService)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	user "github.com/guilherme-santos/user"
+)
+
+// AuditService is a mock of AuditService interface.
+type AuditService struct {
+	ctrl     *gomock.Controller
+	recorder *AuditServiceMockRecorder
+}
+
+// AuditServiceMockRecorder is the mock recorder for AuditService.
+type AuditServiceMockRecorder struct {
+	mock *AuditService
+}
+
+// NewAuditService creates a new mock instance.
+func NewAuditService(ctrl *gomock.Controller) *AuditService {
+	mock := &AuditService{ctrl: ctrl}
+	mock.recorder = &AuditServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *AuditService) EXPECT() *AuditServiceMockRecorder {
+	return m.recorder
+}
+
+// AuditLog mocks base method.
+func (m *AuditService) AuditLog(arg0 context.Context, arg1 string, arg2 *user.AuditListOptions) (*user.AuditListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuditLog", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*user.AuditListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuditLog indicates an expected call of AuditLog.
+func (mr *AuditServiceMockRecorder) AuditLog(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuditLog", reflect.TypeOf((*AuditService)(nil).AuditLog), arg0, arg1, arg2)
+}
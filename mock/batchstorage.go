@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/guilherme-santos/user (interfaces: BatchStorage)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	user "github.com/guilherme-santos/user"
+)
+
+// BatchStorage is a mock of BatchStorage interface.
+type BatchStorage struct {
+	ctrl     *gomock.Controller
+	recorder *BatchStorageMockRecorder
+}
+
+// BatchStorageMockRecorder is the mock recorder for BatchStorage.
+type BatchStorageMockRecorder struct {
+	mock *BatchStorage
+}
+
+// NewBatchStorage creates a new mock instance.
+func NewBatchStorage(ctrl *gomock.Controller) *BatchStorage {
+	mock := &BatchStorage{ctrl: ctrl}
+	mock.recorder = &BatchStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *BatchStorage) EXPECT() *BatchStorageMockRecorder {
+	return m.recorder
+}
+
+// CreateBatch mocks base method.
+func (m *BatchStorage) CreateBatch(arg0 context.Context, arg1 []*user.User, arg2 []*user.AuditEntry, arg3 bool) ([]error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *BatchStorageMockRecorder) CreateBatch(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*BatchStorage)(nil).CreateBatch), arg0, arg1, arg2, arg3)
+}
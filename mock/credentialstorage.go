@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/guilherme-santos/user (interfaces: CredentialStorage)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// CredentialStorage is a mock of CredentialStorage interface.
+type CredentialStorage struct {
+	ctrl     *gomock.Controller
+	recorder *CredentialStorageMockRecorder
+}
+
+// CredentialStorageMockRecorder is the mock recorder for CredentialStorage.
+type CredentialStorageMockRecorder struct {
+	mock *CredentialStorage
+}
+
+// NewCredentialStorage creates a new mock instance.
+func NewCredentialStorage(ctrl *gomock.Controller) *CredentialStorage {
+	mock := &CredentialStorage{ctrl: ctrl}
+	mock.recorder = &CredentialStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *CredentialStorage) EXPECT() *CredentialStorageMockRecorder {
+	return m.recorder
+}
+
+// GetCredentials mocks base method.
+func (m *CredentialStorage) GetCredentials(arg0 context.Context, arg1 string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCredentials", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCredentials indicates an expected call of GetCredentials.
+func (mr *CredentialStorageMockRecorder) GetCredentials(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCredentials", reflect.TypeOf((*CredentialStorage)(nil).GetCredentials), arg0, arg1)
+}
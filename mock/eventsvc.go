@@ -35,6 +35,34 @@ func (m *EventService) EXPECT() *EventServiceMockRecorder {
 	return m.recorder
 }
 
+// RoleGranted mocks base method.
+func (m *EventService) RoleGranted(arg0 context.Context, arg1 *user.RoleAssignment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RoleGranted", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RoleGranted indicates an expected call of RoleGranted.
+func (mr *EventServiceMockRecorder) RoleGranted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RoleGranted", reflect.TypeOf((*EventService)(nil).RoleGranted), arg0, arg1)
+}
+
+// RoleRevoked mocks base method.
+func (m *EventService) RoleRevoked(arg0 context.Context, arg1 *user.RoleAssignment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RoleRevoked", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RoleRevoked indicates an expected call of RoleRevoked.
+func (mr *EventServiceMockRecorder) RoleRevoked(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RoleRevoked", reflect.TypeOf((*EventService)(nil).RoleRevoked), arg0, arg1)
+}
+
 // UserCreated mocks base method.
 func (m *EventService) UserCreated(arg0 context.Context, arg1 *user.User) error {
 	m.ctrl.T.Helper()
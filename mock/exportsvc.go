@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/guilherme-santos/user (interfaces: ExportService)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	user "github.com/guilherme-santos/user"
+)
+
+// ExportService is a mock of ExportService interface.
+type ExportService struct {
+	ctrl     *gomock.Controller
+	recorder *ExportServiceMockRecorder
+}
+
+// ExportServiceMockRecorder is the mock recorder for ExportService.
+type ExportServiceMockRecorder struct {
+	mock *ExportService
+}
+
+// NewExportService creates a new mock instance.
+func NewExportService(ctrl *gomock.Controller) *ExportService {
+	mock := &ExportService{ctrl: ctrl}
+	mock.recorder = &ExportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *ExportService) EXPECT() *ExportServiceMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *ExportService) Export(arg0 context.Context, arg1 *user.ListOptions, arg2 func(*user.User) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *ExportServiceMockRecorder) Export(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*ExportService)(nil).Export), arg0, arg1, arg2)
+}
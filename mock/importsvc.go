@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/guilherme-santos/user (interfaces: ImportService)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	user "github.com/guilherme-santos/user"
+)
+
+// ImportService is a mock of ImportService interface.
+type ImportService struct {
+	ctrl     *gomock.Controller
+	recorder *ImportServiceMockRecorder
+}
+
+// ImportServiceMockRecorder is the mock recorder for ImportService.
+type ImportServiceMockRecorder struct {
+	mock *ImportService
+}
+
+// NewImportService creates a new mock instance.
+func NewImportService(ctrl *gomock.Controller) *ImportService {
+	mock := &ImportService{ctrl: ctrl}
+	mock.recorder = &ImportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *ImportService) EXPECT() *ImportServiceMockRecorder {
+	return m.recorder
+}
+
+// Import mocks base method.
+func (m *ImportService) Import(arg0 context.Context, arg1 user.ImportReader, arg2 *user.ImportOptions, arg3 func(*user.ImportResult)) (*user.ImportSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*user.ImportSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *ImportServiceMockRecorder) Import(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*ImportService)(nil).Import), arg0, arg1, arg2, arg3)
+}
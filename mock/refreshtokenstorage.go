@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/guilherme-santos/user (interfaces: RefreshTokenStorage)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	user "github.com/guilherme-santos/user"
+)
+
+// RefreshTokenStorage is a mock of RefreshTokenStorage interface.
+type RefreshTokenStorage struct {
+	ctrl     *gomock.Controller
+	recorder *RefreshTokenStorageMockRecorder
+}
+
+// RefreshTokenStorageMockRecorder is the mock recorder for RefreshTokenStorage.
+type RefreshTokenStorageMockRecorder struct {
+	mock *RefreshTokenStorage
+}
+
+// NewRefreshTokenStorage creates a new mock instance.
+func NewRefreshTokenStorage(ctrl *gomock.Controller) *RefreshTokenStorage {
+	mock := &RefreshTokenStorage{ctrl: ctrl}
+	mock.recorder = &RefreshTokenStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *RefreshTokenStorage) EXPECT() *RefreshTokenStorageMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *RefreshTokenStorage) Consume(arg0 context.Context, arg1 string) (*user.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", arg0, arg1)
+	ret0, _ := ret[0].(*user.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *RefreshTokenStorageMockRecorder) Consume(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*RefreshTokenStorage)(nil).Consume), arg0, arg1)
+}
+
+// Create mocks base method.
+func (m *RefreshTokenStorage) Create(arg0 context.Context, arg1 *user.RefreshToken, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *RefreshTokenStorageMockRecorder) Create(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*RefreshTokenStorage)(nil).Create), arg0, arg1, arg2)
+}
+
+// RevokeFamily mocks base method.
+func (m *RefreshTokenStorage) RevokeFamily(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFamily", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFamily indicates an expected call of RevokeFamily.
+func (mr *RefreshTokenStorageMockRecorder) RevokeFamily(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFamily", reflect.TypeOf((*RefreshTokenStorage)(nil).RevokeFamily), arg0, arg1)
+}
+
+// RevokeUser mocks base method.
+func (m *RefreshTokenStorage) RevokeUser(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUser indicates an expected call of RevokeUser.
+func (mr *RefreshTokenStorageMockRecorder) RevokeUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUser", reflect.TypeOf((*RefreshTokenStorage)(nil).RevokeUser), arg0, arg1)
+}
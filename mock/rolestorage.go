@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/guilherme-santos/user (interfaces: RoleStorage)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	user "github.com/guilherme-santos/user"
+)
+
+// RoleStorage is a mock of RoleStorage interface.
+type RoleStorage struct {
+	ctrl     *gomock.Controller
+	recorder *RoleStorageMockRecorder
+}
+
+// RoleStorageMockRecorder is the mock recorder for RoleStorage.
+type RoleStorageMockRecorder struct {
+	mock *RoleStorage
+}
+
+// NewRoleStorage creates a new mock instance.
+func NewRoleStorage(ctrl *gomock.Controller) *RoleStorage {
+	mock := &RoleStorage{ctrl: ctrl}
+	mock.recorder = &RoleStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *RoleStorage) EXPECT() *RoleStorageMockRecorder {
+	return m.recorder
+}
+
+// Grant mocks base method.
+func (m *RoleStorage) Grant(arg0 context.Context, arg1 *user.RoleAssignment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Grant", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Grant indicates an expected call of Grant.
+func (mr *RoleStorageMockRecorder) Grant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Grant", reflect.TypeOf((*RoleStorage)(nil).Grant), arg0, arg1)
+}
+
+// Revoke mocks base method.
+func (m *RoleStorage) Revoke(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *RoleStorageMockRecorder) Revoke(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*RoleStorage)(nil).Revoke), arg0, arg1, arg2)
+}
+
+// Roles mocks base method.
+func (m *RoleStorage) Roles(arg0 context.Context, arg1 string) ([]*user.RoleAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Roles", arg0, arg1)
+	ret0, _ := ret[0].([]*user.RoleAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Roles indicates an expected call of Roles.
+func (mr *RoleStorageMockRecorder) Roles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Roles", reflect.TypeOf((*RoleStorage)(nil).Roles), arg0, arg1)
+}
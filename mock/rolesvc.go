@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/guilherme-santos/user (interfaces: RoleService)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	user "github.com/guilherme-santos/user"
+)
+
+// RoleService is a mock of RoleService interface.
+type RoleService struct {
+	ctrl     *gomock.Controller
+	recorder *RoleServiceMockRecorder
+}
+
+// RoleServiceMockRecorder is the mock recorder for RoleService.
+type RoleServiceMockRecorder struct {
+	mock *RoleService
+}
+
+// NewRoleService creates a new mock instance.
+func NewRoleService(ctrl *gomock.Controller) *RoleService {
+	mock := &RoleService{ctrl: ctrl}
+	mock.recorder = &RoleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *RoleService) EXPECT() *RoleServiceMockRecorder {
+	return m.recorder
+}
+
+// GrantRole mocks base method.
+func (m *RoleService) GrantRole(arg0 context.Context, arg1, arg2 string) (*user.RoleAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantRole", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*user.RoleAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GrantRole indicates an expected call of GrantRole.
+func (mr *RoleServiceMockRecorder) GrantRole(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantRole", reflect.TypeOf((*RoleService)(nil).GrantRole), arg0, arg1, arg2)
+}
+
+// ListRoles mocks base method.
+func (m *RoleService) ListRoles(arg0 context.Context, arg1 string) ([]*user.RoleAssignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoles", arg0, arg1)
+	ret0, _ := ret[0].([]*user.RoleAssignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoles indicates an expected call of ListRoles.
+func (mr *RoleServiceMockRecorder) ListRoles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoles", reflect.TypeOf((*RoleService)(nil).ListRoles), arg0, arg1)
+}
+
+// RevokeRole mocks base method.
+func (m *RoleService) RevokeRole(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRole", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRole indicates an expected call of RevokeRole.
+func (mr *RoleServiceMockRecorder) RevokeRole(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRole", reflect.TypeOf((*RoleService)(nil).RevokeRole), arg0, arg1, arg2)
+}
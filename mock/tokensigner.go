@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/guilherme-santos/user (interfaces: TokenSigner)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	user "github.com/guilherme-santos/user"
+)
+
+// TokenSigner is a mock of TokenSigner interface.
+type TokenSigner struct {
+	ctrl     *gomock.Controller
+	recorder *TokenSignerMockRecorder
+}
+
+// TokenSignerMockRecorder is the mock recorder for TokenSigner.
+type TokenSignerMockRecorder struct {
+	mock *TokenSigner
+}
+
+// NewTokenSigner creates a new mock instance.
+func NewTokenSigner(ctrl *gomock.Controller) *TokenSigner {
+	mock := &TokenSigner{ctrl: ctrl}
+	mock.recorder = &TokenSignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *TokenSigner) EXPECT() *TokenSignerMockRecorder {
+	return m.recorder
+}
+
+// Sign mocks base method.
+func (m *TokenSigner) Sign(arg0 *user.TokenClaims) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sign", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sign indicates an expected call of Sign.
+func (mr *TokenSignerMockRecorder) Sign(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*TokenSigner)(nil).Sign), arg0)
+}
+
+// Verify mocks base method.
+func (m *TokenSigner) Verify(arg0 string) (*user.TokenClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", arg0)
+	ret0, _ := ret[0].(*user.TokenClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *TokenSignerMockRecorder) Verify(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*TokenSigner)(nil).Verify), arg0)
+}
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/guilherme-santos/user (interfaces: TokenService)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	user "github.com/guilherme-santos/user"
+)
+
+// TokenService is a mock of TokenService interface.
+type TokenService struct {
+	ctrl     *gomock.Controller
+	recorder *TokenServiceMockRecorder
+}
+
+// TokenServiceMockRecorder is the mock recorder for TokenService.
+type TokenServiceMockRecorder struct {
+	mock *TokenService
+}
+
+// NewTokenService creates a new mock instance.
+func NewTokenService(ctrl *gomock.Controller) *TokenService {
+	mock := &TokenService{ctrl: ctrl}
+	mock.recorder = &TokenServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *TokenService) EXPECT() *TokenServiceMockRecorder {
+	return m.recorder
+}
+
+// IssueToken mocks base method.
+func (m *TokenService) IssueToken(arg0 context.Context, arg1, arg2 string) (*user.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueToken", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*user.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueToken indicates an expected call of IssueToken.
+func (mr *TokenServiceMockRecorder) IssueToken(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueToken", reflect.TypeOf((*TokenService)(nil).IssueToken), arg0, arg1, arg2)
+}
+
+// RefreshToken mocks base method.
+func (m *TokenService) RefreshToken(arg0 context.Context, arg1 string) (*user.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", arg0, arg1)
+	ret0, _ := ret[0].(*user.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *TokenServiceMockRecorder) RefreshToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*TokenService)(nil).RefreshToken), arg0, arg1)
+}
+
+// VerifyAccessToken mocks base method.
+func (m *TokenService) VerifyAccessToken(arg0 context.Context, arg1 string) (*user.TokenClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAccessToken", arg0, arg1)
+	ret0, _ := ret[0].(*user.TokenClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAccessToken indicates an expected call of VerifyAccessToken.
+func (mr *TokenServiceMockRecorder) VerifyAccessToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAccessToken", reflect.TypeOf((*TokenService)(nil).VerifyAccessToken), arg0, arg1)
+}
@@ -0,0 +1,10 @@
+package mysql
+
+import (
+	"github.com/guilherme-santos/user/sqlstorage"
+)
+
+// NewAPIKeyStorage returns the storage of the API keys in the api_key table.
+func NewAPIKeyStorage(db *DB) *sqlstorage.APIKeyStorage {
+	return sqlstorage.NewAPIKeyStorage(db, Dialect)
+}
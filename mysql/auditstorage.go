@@ -0,0 +1,11 @@
+package mysql
+
+import (
+	"github.com/guilherme-santos/user/sqlstorage"
+)
+
+// NewAuditStorage returns the storage of the audit log in the user_audit
+// table, which has triggers to reject any UPDATE or DELETE.
+func NewAuditStorage(db *DB) *sqlstorage.AuditStorage {
+	return sqlstorage.NewAuditStorage(db, Dialect)
+}
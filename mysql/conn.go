@@ -7,13 +7,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/guilherme-santos/user/sqlstorage"
+
 	"github.com/go-sql-driver/mysql"
-	"github.com/golang-migrate/migrate/v4"
-	migratemysql "github.com/golang-migrate/migrate/v4/database/mysql"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
-func NewConnection(host string, port int, user, password, database, migrationDir string) (*sql.DB, error) {
+// NewConnection connects to the database, migrations aren't applied, see
+// NewMigrator.
+func NewConnection(host string, port int, user, password, database string) (*sql.DB, error) {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&multiStatements=true", user, password, host, port, database)
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
@@ -27,25 +28,18 @@ func NewConnection(host string, port int, user, password, database, migrationDir
 	if err != nil {
 		return nil, err
 	}
-
-	cfg := &migratemysql.Config{
-		DatabaseName: database,
-	}
-	driver, err := migratemysql.WithInstance(db, cfg)
-	if err != nil {
-		return nil, err
-	}
-	m, err := migrate.NewWithDatabaseInstance("file://"+migrationDir, "mysql", driver)
-	if err != nil {
-		return nil, err
-	}
-	err = m.Up()
-	if err != nil && err != migrate.ErrNoChange {
-		return nil, err
-	}
 	return db, nil
 }
 
+// Dialect is used by the storages of the sqlstorage package.
+var Dialect = sqlstorage.Dialect{
+	Placeholder:      sqlstorage.QuestionPlaceholder,
+	IsDuplicateError: IsDuplicateError,
+	UserTable:        "user",
+	UserEmailKey:     "user.tenant_email",
+	RoleKey:          "user_role.PRIMARY",
+}
+
 func IsDuplicateError(err error, field string) bool {
 	var merr *mysql.MySQLError
 	if errors.As(err, &merr) {
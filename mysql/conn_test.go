@@ -0,0 +1,25 @@
+package mysql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDuplicateError(t *testing.T) {
+	mysqlErr := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'default-a@b.com' for key 'user.tenant_email'"}
+	assert.True(t, IsDuplicateError(mysqlErr, "user.tenant_email"))
+	assert.False(t, IsDuplicateError(mysqlErr, "user.PRIMARY"))
+
+	// go-mysql-server doesn't name the key, see embeddedDialect
+	gmsErr := &mysql.MySQLError{Number: 1062, Message: "duplicate unique key given: [default,a@b.com]"}
+	assert.False(t, IsDuplicateError(gmsErr, "user.tenant_email"))
+	assert.True(t, embeddedDialect.IsDuplicateError(gmsErr, "user.tenant_email"))
+	assert.False(t, embeddedDialect.IsDuplicateError(gmsErr, "user_role.PRIMARY"))
+	assert.True(t, embeddedDialect.IsDuplicateError(mysqlErr, "user.tenant_email"))
+
+	assert.False(t, IsDuplicateError(&mysql.MySQLError{Number: 1064}, "user.tenant_email"))
+	assert.False(t, IsDuplicateError(errors.New("Duplicate entry"), "user.tenant_email"))
+}
@@ -0,0 +1,286 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/metrics"
+	"github.com/guilherme-santos/user/sqlstorage"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/guilherme-santos/user/mysql")
+
+// DB wraps *sql.DB instrumenting every query: a span is created, the latency
+// is recorded per caller (see withCaller) and queries slower than the
+// threshold are logged with the context logger. Args are never logged, only
+// its count.
+type DB struct {
+	*sql.DB
+
+	slowThreshold time.Duration
+	explain       bool
+}
+
+// DBOption configures a DB.
+type DBOption func(*DB)
+
+// WithSlowQueryThreshold logs queries taking longer than d, zero disables it.
+func WithSlowQueryThreshold(d time.Duration) DBOption {
+	return func(db *DB) {
+		db.slowThreshold = d
+	}
+}
+
+// WithExplain logs, as debug, the EXPLAIN of slow queries run by List methods.
+func WithExplain(explain bool) DBOption {
+	return func(db *DB) {
+		db.explain = explain
+	}
+}
+
+func NewDB(db *sql.DB, opts ...DBOption) *DB {
+	d := &DB{
+		DB:            db,
+		slowThreshold: 200 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	q := db.start(ctx, query, args)
+	res, err := db.DB.ExecContext(q.ctx, query, args...)
+	q.end(res, err)
+	return res, err
+}
+
+// QueryContext only measures the time until the first row is available.
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	q := db.start(ctx, query, args)
+	rows, err := db.DB.QueryContext(q.ctx, query, args...)
+	q.end(nil, err)
+	return rows, err
+}
+
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	q := db.start(ctx, query, args)
+	row := db.DB.QueryRowContext(q.ctx, query, args...)
+	q.end(nil, row.Err())
+	return row
+}
+
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (sqlstorage.Tx, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, db: db}, nil
+}
+
+// Tx wraps *sql.Tx instrumenting every query like DB.
+type Tx struct {
+	*sql.Tx
+
+	db *DB
+}
+
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	q := tx.db.start(ctx, query, args)
+	res, err := tx.Tx.ExecContext(q.ctx, query, args...)
+	q.end(res, err)
+	return res, err
+}
+
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	q := tx.db.start(ctx, query, args)
+	rows, err := tx.Tx.QueryContext(q.ctx, query, args...)
+	q.end(nil, err)
+	return rows, err
+}
+
+func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	q := tx.db.start(ctx, query, args)
+	row := tx.Tx.QueryRowContext(q.ctx, query, args...)
+	q.end(nil, row.Err())
+	return row
+}
+
+func (tx *Tx) PrepareContext(ctx context.Context, query string) (sqlstorage.Stmt, error) {
+	stmt, err := tx.Tx.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &Stmt{Stmt: stmt, db: tx.db, query: query}, nil
+}
+
+// Stmt wraps *sql.Stmt instrumenting every execution like DB.
+type Stmt struct {
+	*sql.Stmt
+
+	db    *DB
+	query string
+}
+
+func (s *Stmt) ExecContext(ctx context.Context, args ...interface{}) (sql.Result, error) {
+	q := s.db.start(ctx, s.query, args)
+	res, err := s.Stmt.ExecContext(q.ctx, args...)
+	q.end(res, err)
+	return res, err
+}
+
+// instrumentedQuery holds the state of a query being executed.
+type instrumentedQuery struct {
+	db     *DB
+	ctx    context.Context
+	span   trace.Span
+	raw    string
+	query  string
+	args   []interface{}
+	caller string
+	start  time.Time
+}
+
+func (db *DB) start(ctx context.Context, query string, args []interface{}) *instrumentedQuery {
+	q := &instrumentedQuery{
+		db:     db,
+		raw:    query,
+		query:  normalizeQuery(query),
+		args:   args,
+		caller: callerFrom(ctx),
+	}
+	q.ctx, q.span = tracer.Start(ctx, "mysql "+operation(q.query),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "mysql"),
+			attribute.String("db.statement", q.query),
+			attribute.String("code.function", q.caller),
+		),
+	)
+	q.start = time.Now()
+	return q
+}
+
+func (q *instrumentedQuery) end(res sql.Result, err error) {
+	elapsed := time.Since(q.start)
+	defer q.span.End()
+
+	metrics.DBQueryDuration.WithLabelValues(q.caller, operation(q.query)).Observe(elapsed.Seconds())
+	if err != nil && err != sql.ErrNoRows {
+		q.span.RecordError(err)
+		q.span.SetStatus(codes.Error, err.Error())
+	}
+	if q.db.slowThreshold <= 0 || elapsed < q.db.slowThreshold {
+		return
+	}
+	metrics.DBSlowQueries.WithLabelValues(q.caller).Inc()
+
+	fields := logrus.Fields{
+		"sql":        q.query,
+		"args":       len(q.args),
+		"caller":     q.caller,
+		"elapsed_ms": float64(elapsed.Nanoseconds()) / float64(time.Millisecond),
+		"elapsed":    elapsed.String(),
+	}
+	if res != nil {
+		if n, err := res.RowsAffected(); err == nil {
+			fields["rows_affected"] = n
+		}
+	}
+	log := user.Logger(q.ctx).WithFields(fields)
+	if err != nil {
+		log = log.WithError(err)
+	}
+	log.Warn("slow query")
+
+	if q.db.explain && strings.HasSuffix(q.caller, ".List") && operation(q.query) == "SELECT" {
+		plan, err := q.db.Explain(q.ctx, q.raw, q.args...)
+		if err != nil {
+			log.WithError(err).Debug("unable to explain slow query")
+			return
+		}
+		log.WithField("plan", plan).Debug("slow query plan")
+	}
+}
+
+// Explain runs EXPLAIN of query returning one map per row of the plan.
+func (db *DB) Explain(ctx context.Context, query string, args ...interface{}) ([]map[string]string, error) {
+	rows, err := db.DB.QueryContext(ctx, "EXPLAIN "+query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var plan []map[string]string
+
+	for rows.Next() {
+		values := make([]sql.NullString, len(cols))
+		dest := make([]interface{}, len(cols))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		err = rows.Scan(dest...)
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]string, len(cols))
+		for i, col := range cols {
+			if values[i].Valid {
+				row[col] = values[i].String
+			}
+		}
+		plan = append(plan, row)
+	}
+	return plan, rows.Err()
+}
+
+var (
+	spacesRegexp   = regexp.MustCompile(`\s+`)
+	literalsRegexp = regexp.MustCompile(`'(?:[^'\\]|\\.)*'|\b\d+\b`)
+)
+
+// normalizeQuery collapses whitespaces and replaces literals by ?, so queries
+// only differing by LIMIT or OFFSET are the same.
+func normalizeQuery(query string) string {
+	query = spacesRegexp.ReplaceAllString(strings.TrimSpace(query), " ")
+	return literalsRegexp.ReplaceAllString(query, "?")
+}
+
+// operation returns the first keyword of query, e.g. SELECT.
+func operation(query string) string {
+	if i := strings.IndexByte(query, ' '); i > 0 {
+		query = query[:i]
+	}
+	return strings.ToUpper(query)
+}
+
+// withCaller returns a copy of ctx whose queries are recorded as made by
+// caller, e.g. UserStorage.Get, in spans, metrics and slow query logs. Every
+// exported method of the storages sets it, the ones of sqlstorage included.
+func withCaller(ctx context.Context, caller string) context.Context {
+	return sqlstorage.WithCaller(ctx, caller)
+}
+
+// callerFrom returns the caller stored by withCaller.
+func callerFrom(ctx context.Context) string {
+	caller := sqlstorage.Caller(ctx)
+	if caller == "" {
+		return "unknown"
+	}
+	return caller
+}
@@ -0,0 +1,41 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{
+			query: `
+				SELECT id, email
+				FROM user
+				WHERE removed_at IS NULL LIMIT 10 OFFSET 20`,
+			expected: "SELECT id, email FROM user WHERE removed_at IS NULL LIMIT ? OFFSET ?",
+		},
+		{
+			query:    "UPDATE user SET country = 'DE', removed_at = NOW() WHERE id = ?",
+			expected: "UPDATE user SET country = ?, removed_at = NOW() WHERE id = ?",
+		},
+		{
+			query:    "SELECT user2.id FROM user2",
+			expected: "SELECT user2.id FROM user2",
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, normalizeQuery(tt.query))
+	}
+}
+
+func TestCaller(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "unknown", callerFrom(ctx))
+	assert.Equal(t, "UserStorage.Get", callerFrom(withCaller(ctx, "UserStorage.Get")))
+}
@@ -0,0 +1,14 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/guilherme-santos/user/sqlstorage"
+)
+
+// CheckMigrations returns an error when the schema is behind the migrations
+// embedded in the binary, see sqlstorage.CheckMigrations.
+func CheckMigrations(ctx context.Context, db *sql.DB) error {
+	return sqlstorage.CheckMigrations(ctx, db, migrations)
+}
@@ -0,0 +1,140 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guilherme-santos/user/sqlstorage"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratemysql "github.com/golang-migrate/migrate/v4/database/mysql"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// migrations are embedded, so the binary doesn't depend on the SQL files.
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// migrationLock is the name of the advisory lock held while migrating.
+const migrationLock = "usersvc_migrate"
+
+var ErrMigrationLocked = errors.New("another instance is migrating the schema")
+
+// Migrator applies the embedded migrations to the database, holding an
+// advisory lock so only one instance migrates at a time.
+type Migrator struct {
+	m           *migrate.Migrate
+	conn        *sql.Conn
+	lockTimeout time.Duration
+}
+
+// NewMigrator returns a Migrator which holds a connection of db until it's
+// closed, it waits up to lockTimeout for other instances migrating.
+func NewMigrator(db *sql.DB, database string, lockTimeout time.Duration) (*Migrator, error) {
+	src, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	// the lock is per connection, so the same one is used by migrate
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// WithConnection doesn't close db when the migrator is closed, and the
+	// lock of migrate is replaced by ours which waits lockTimeout
+	driver, err := migratemysql.WithConnection(ctx, conn, &migratemysql.Config{
+		DatabaseName: database,
+		NoLock:       true,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	m, err := migrate.NewWithInstance("iofs", src, "mysql", driver)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &Migrator{
+		m:           m,
+		conn:        conn,
+		lockTimeout: lockTimeout,
+	}, nil
+}
+
+// Up applies all migrations not applied yet.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.locked(ctx, func() error {
+		return ignoreNoChange(m.m.Up())
+	})
+}
+
+// Down rolls back the last steps migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.locked(ctx, func() error {
+		return ignoreNoChange(m.m.Steps(-steps))
+	})
+}
+
+// Force sets the version of the schema without running any migration and
+// clears the dirty flag, e.g. after fixing a failed migration by hand.
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	return m.locked(ctx, func() error {
+		return m.m.Force(version)
+	})
+}
+
+func (m *Migrator) Status() (*sqlstorage.MigrationStatus, error) {
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, err
+	}
+	latest, err := sqlstorage.LatestMigration(migrations)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlstorage.MigrationStatus{
+		Version: version,
+		Dirty:   dirty,
+		Latest:  latest,
+	}, nil
+}
+
+// Close releases the connection, db is kept open.
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	if srcErr != nil {
+		return srcErr
+	}
+	return dbErr
+}
+
+// locked runs fn holding the advisory lock, it returns ErrMigrationLocked if
+// it isn't released in lockTimeout.
+func (m *Migrator) locked(ctx context.Context, fn func() error) error {
+	var ok sql.NullBool
+	err := m.conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", migrationLock, int(m.lockTimeout.Seconds())).Scan(&ok)
+	if err != nil {
+		return fmt.Errorf("unable to lock migrations: %w", err)
+	}
+	if !ok.Bool {
+		return ErrMigrationLocked
+	}
+	defer m.conn.ExecContext(context.WithoutCancel(ctx), "SELECT RELEASE_LOCK(?)", migrationLock)
+
+	return fn()
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,29 @@
+package mysql
+
+import (
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/guilherme-santos/user/sqlstorage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestMigration(t *testing.T) {
+	files, err := fs.Glob(migrations, "migrations/*.up.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	sort.Strings(files)
+
+	name := strings.TrimPrefix(files[len(files)-1], "migrations/")
+	expected, err := strconv.ParseUint(name[:strings.Index(name, "_")], 10, 64)
+	require.NoError(t, err)
+
+	latest, err := sqlstorage.LatestMigration(migrations)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(expected), latest)
+}
@@ -0,0 +1,3 @@
+DROP TRIGGER `user_audit_no_delete`;
+DROP TRIGGER `user_audit_no_update`;
+DROP TABLE `user_audit`;
@@ -0,0 +1,20 @@
+CREATE TABLE `user_audit` (
+  `id` CHAR(20) NOT NULL,
+  `user_id` CHAR(20) NOT NULL,
+  `action` VARCHAR(50) NOT NULL,
+  `actor` VARCHAR(255) NOT NULL,
+  `request_id` VARCHAR(255) NOT NULL DEFAULT '',
+  `changes` JSON NOT NULL,
+  `created_at` TIMESTAMP(6)
+    NOT NULL
+    DEFAULT CURRENT_TIMESTAMP(6),
+  PRIMARY KEY (`id`),
+  INDEX (`user_id`, `created_at`)
+) ENGINE = InnoDB;
+
+-- The audit log is append-only.
+CREATE TRIGGER `user_audit_no_update` BEFORE UPDATE ON `user_audit`
+  FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'user_audit is append-only';
+
+CREATE TRIGGER `user_audit_no_delete` BEFORE DELETE ON `user_audit`
+  FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'user_audit is append-only';
@@ -0,0 +1,14 @@
+CREATE TABLE `api_key` (
+  `id` CHAR(20) NOT NULL,
+  `name` VARCHAR(255) NOT NULL,
+  `key_hash` CHAR(64) NOT NULL,
+  `scopes` JSON NOT NULL,
+  `expires_at` TIMESTAMP NULL,
+  `last_used_at` TIMESTAMP NULL,
+  `created_at` TIMESTAMP
+    NOT NULL
+    DEFAULT CURRENT_TIMESTAMP,
+  `revoked_at` TIMESTAMP NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX (`key_hash`)
+) ENGINE = InnoDB;
@@ -0,0 +1,15 @@
+CREATE TABLE `refresh_token` (
+  `id` CHAR(20) NOT NULL,
+  `family_id` CHAR(20) NOT NULL,
+  `user_id` CHAR(20) NOT NULL,
+  `token_hash` CHAR(64) NOT NULL,
+  `expires_at` TIMESTAMP NOT NULL,
+  `created_at` TIMESTAMP
+    NOT NULL
+    DEFAULT CURRENT_TIMESTAMP,
+  `used_at` TIMESTAMP NULL,
+  `revoked_at` TIMESTAMP NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX (`token_hash`),
+  INDEX (`family_id`)
+) ENGINE = InnoDB;
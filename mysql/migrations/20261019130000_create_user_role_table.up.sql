@@ -0,0 +1,9 @@
+CREATE TABLE `user_role` (
+  `user_id` CHAR(20) NOT NULL,
+  `role` VARCHAR(32) NOT NULL,
+  `granted_by` VARCHAR(255) NOT NULL,
+  `granted_at` TIMESTAMP
+    NOT NULL
+    DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`user_id`, `role`)
+) ENGINE = InnoDB;
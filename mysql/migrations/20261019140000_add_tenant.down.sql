@@ -0,0 +1,10 @@
+ALTER TABLE `refresh_token`
+  DROP COLUMN `tenant_id`;
+
+ALTER TABLE `api_key`
+  DROP COLUMN `tenant_id`;
+
+ALTER TABLE `user`
+  DROP INDEX `tenant_email`,
+  ADD UNIQUE INDEX `email` (`email`),
+  DROP COLUMN `tenant_id`;
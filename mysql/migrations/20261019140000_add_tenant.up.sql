@@ -0,0 +1,10 @@
+ALTER TABLE `user`
+  ADD COLUMN `tenant_id` VARCHAR(64) NOT NULL DEFAULT 'default' AFTER `id`,
+  DROP INDEX `email`,
+  ADD UNIQUE INDEX `tenant_email` (`tenant_id`, `email`);
+
+ALTER TABLE `api_key`
+  ADD COLUMN `tenant_id` VARCHAR(64) NULL AFTER `scopes`;
+
+ALTER TABLE `refresh_token`
+  ADD COLUMN `tenant_id` VARCHAR(64) NOT NULL DEFAULT 'default' AFTER `user_id`;
@@ -0,0 +1,2 @@
+ALTER TABLE `refresh_token`
+  DROP INDEX `user_id`;
@@ -0,0 +1,2 @@
+ALTER TABLE `refresh_token`
+  ADD INDEX `user_id` (`user_id`);
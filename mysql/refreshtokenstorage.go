@@ -0,0 +1,11 @@
+package mysql
+
+import (
+	"github.com/guilherme-santos/user/sqlstorage"
+)
+
+// NewRefreshTokenStorage returns the storage of the refresh tokens in the
+// refresh_token table.
+func NewRefreshTokenStorage(db *DB) *sqlstorage.RefreshTokenStorage {
+	return sqlstorage.NewRefreshTokenStorage(db, Dialect)
+}
@@ -0,0 +1,11 @@
+package mysql
+
+import (
+	"github.com/guilherme-santos/user/sqlstorage"
+)
+
+// NewRoleStorage returns the storage of the roles granted to users in the
+// user_role table.
+func NewRoleStorage(db *DB) *sqlstorage.RoleStorage {
+	return sqlstorage.NewRoleStorage(db, Dialect)
+}
@@ -0,0 +1,139 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/sqlstorage"
+	"github.com/guilherme-santos/user/storagetest"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/server"
+	gmssql "github.com/dolthub/go-mysql-server/sql"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUserStorage runs the conformance suite against an embedded
+// MySQL-compatible server, or against the database of USERSVC_TEST_MYSQL_DSN
+// when it's set, e.g.
+// root:root@tcp(localhost:3306)/user_test?parseTime=true&multiStatements=true
+func TestUserStorage(t *testing.T) {
+	db, dialect := newDB(t)
+	storagetest.Run(t, func(t *testing.T) user.Storage {
+		return sqlstorage.NewUserStorage(db, dialect)
+	})
+}
+
+func TestAPIKeyStorage(t *testing.T) {
+	storagetest.RunAPIKeys(t, sqlstorage.NewAPIKeyStorage(newDB(t)))
+}
+
+func TestRefreshTokenStorage(t *testing.T) {
+	storagetest.RunRefreshTokens(t, sqlstorage.NewRefreshTokenStorage(newDB(t)))
+}
+
+func TestRoleStorage(t *testing.T) {
+	storagetest.RunRoles(t, sqlstorage.NewRoleStorage(newDB(t)))
+}
+
+// newDB returns a migrated database, see TestUserStorage, and the dialect of
+// its server. It's closed when the test finishes.
+func newDB(t *testing.T) (*DB, sqlstorage.Dialect) {
+	dsn := os.Getenv("USERSVC_TEST_MYSQL_DSN")
+	embedded := dsn == ""
+	if embedded {
+		dsn = startServer(t, "user")
+	}
+	cfg, err := mysql.ParseDSN(dsn)
+	require.NoError(t, err)
+
+	db, err := sql.Open("mysql", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	if embedded {
+		migrateEmbedded(t, db)
+	} else {
+		m, err := NewMigrator(db, cfg.DBName, time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, m.Up(context.Background()))
+		require.NoError(t, m.Close())
+	}
+
+	if embedded {
+		return NewDB(db), embeddedDialect
+	}
+	return NewDB(db), Dialect
+}
+
+// embeddedDialect is Dialect for the embedded server, which doesn't name the
+// key of duplicate entries, e.g. "duplicate unique key given: [default,a]".
+// Its unique keys match any key but the primary ones.
+var embeddedDialect = func() sqlstorage.Dialect {
+	d := Dialect
+	d.IsDuplicateError = func(err error, key string) bool {
+		if IsDuplicateError(err, key) {
+			return true
+		}
+		var merr *mysql.MySQLError
+		if !errors.As(err, &merr) || merr.Number != 1062 {
+			return false
+		}
+		primary := strings.HasSuffix(key, ".PRIMARY")
+		if strings.HasPrefix(merr.Message, "duplicate primary key given") {
+			return primary
+		}
+		return !primary && strings.HasPrefix(merr.Message, "duplicate unique key given")
+	}
+	return d
+}()
+
+// startServer starts an in-memory go-mysql-server with an empty database,
+// it's stopped when the test finishes. It returns its DSN.
+func startServer(t *testing.T, database string) string {
+	pro := memory.NewDBProvider(memory.NewDatabase(database))
+	engine := sqle.NewDefault(pro)
+
+	srv, err := server.NewServer(server.Config{
+		Protocol: "tcp",
+		Address:  "127.0.0.1:0",
+	}, engine, gmssql.NewContext, memory.NewSessionBuilder(pro), nil)
+	require.NoError(t, err)
+	go srv.Start()
+	t.Cleanup(func() { srv.Close() })
+
+	return fmt.Sprintf("root@tcp(%s)/%s?parseTime=true&multiStatements=true", srv.Listener.Addr(), database)
+}
+
+// singleStatementTrigger matches triggers whose body is a single statement,
+// which the embedded server only parses inside of BEGIN ... END.
+var singleStatementTrigger = regexp.MustCompile(`(?s)FOR EACH ROW (SIGNAL[^;]*;)`)
+
+// migrateEmbedded applies the up migrations to the embedded server without
+// Migrator, rewriting the syntax it doesn't parse. The migrations themselves
+// are never changed to suit the server, as applied ones are never run again.
+func migrateEmbedded(t *testing.T, db *sql.DB) {
+	files, err := fs.Glob(migrations, "migrations/*.up.sql")
+	require.NoError(t, err)
+	sort.Strings(files)
+
+	for _, f := range files {
+		b, err := migrations.ReadFile(f)
+		require.NoError(t, err)
+		query := singleStatementTrigger.ReplaceAllString(string(b), "FOR EACH ROW BEGIN $1 END;")
+		_, err = db.Exec(query)
+		require.NoError(t, err, f)
+	}
+}
@@ -0,0 +1,12 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/guilherme-santos/user/sqlstorage"
+)
+
+// NewAPIKeyStorage returns the storage of the API keys in the api_key table.
+func NewAPIKeyStorage(db *sql.DB) *sqlstorage.APIKeyStorage {
+	return sqlstorage.NewAPIKeyStorage(sqlstorage.NewDB(db), Dialect)
+}
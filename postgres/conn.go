@@ -0,0 +1,68 @@
+// Package postgres implements the storages of the service in PostgreSQL, they
+// behave the same as the ones of the mysql package.
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/guilherme-santos/user/sqlstorage"
+
+	"github.com/lib/pq"
+)
+
+// DSN returns the connection string of the database, sslmode is one of the
+// modes supported by lib/pq, e.g. disable or verify-full.
+func DSN(host string, port int, user, password, database, sslmode string) string {
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(user, password),
+		Host:   net.JoinHostPort(host, strconv.Itoa(port)),
+		Path:   "/" + database,
+	}
+	u.RawQuery = url.Values{"sslmode": {sslmode}}.Encode()
+	return u.String()
+}
+
+// NewConnection connects to the database, migrations aren't applied, see
+// NewMigrator.
+func NewConnection(dsn string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetConnMaxLifetime(1 * time.Hour)
+	db.SetMaxIdleConns(2)
+	db.SetMaxOpenConns(10)
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Dialect is used by the storages of the sqlstorage package.
+var Dialect = sqlstorage.Dialect{
+	Placeholder:      sqlstorage.DollarPlaceholder,
+	IsDuplicateError: IsDuplicateError,
+	AbortsTxOnError:  true,
+	UserTable:        "users",
+	UserEmailKey:     "users_tenant_email",
+	RoleKey:          "user_role_pkey",
+}
+
+// IsDuplicateError returns whether err is a violation of the unique
+// constraint.
+func IsDuplicateError(err error, constraint string) bool {
+	var perr *pq.Error
+	if errors.As(err, &perr) {
+		return perr.Code == "23505" && perr.Constraint == constraint
+	}
+	return false
+}
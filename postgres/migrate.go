@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guilherme-santos/user/sqlstorage"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratepostgres "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/lib/pq"
+)
+
+// migrations are embedded, so the binary doesn't depend on the SQL files.
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// migrationLock is the name of the advisory lock held while migrating.
+const migrationLock = "usersvc_migrate"
+
+var ErrMigrationLocked = errors.New("another instance is migrating the schema")
+
+// Migrator applies the embedded migrations to the database, holding an
+// advisory lock so only one instance migrates at a time.
+type Migrator struct {
+	m           *migrate.Migrate
+	conn        *sql.Conn
+	lockTimeout time.Duration
+}
+
+// NewMigrator returns a Migrator connected to dsn until it's closed, it waits
+// up to lockTimeout for other instances migrating. It has its own connection
+// because migrate closes the database when it's closed.
+func NewMigrator(dsn string, lockTimeout time.Duration) (*Migrator, error) {
+	src, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := migratepostgres.WithInstance(db, &migratepostgres.Config{})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		driver.Close()
+		return nil, err
+	}
+	// the lock is per session, so it's held by the same connection
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		m.Close()
+		return nil, err
+	}
+	return &Migrator{
+		m:           m,
+		conn:        conn,
+		lockTimeout: lockTimeout,
+	}, nil
+}
+
+// Up applies all migrations not applied yet.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.locked(ctx, func() error {
+		return ignoreNoChange(m.m.Up())
+	})
+}
+
+// Down rolls back the last steps migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.locked(ctx, func() error {
+		return ignoreNoChange(m.m.Steps(-steps))
+	})
+}
+
+// Force sets the version of the schema without running any migration and
+// clears the dirty flag, e.g. after fixing a failed migration by hand.
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	return m.locked(ctx, func() error {
+		return m.m.Force(version)
+	})
+}
+
+func (m *Migrator) Status() (*sqlstorage.MigrationStatus, error) {
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, err
+	}
+	latest, err := sqlstorage.LatestMigration(migrations)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlstorage.MigrationStatus{
+		Version: version,
+		Dirty:   dirty,
+		Latest:  latest,
+	}, nil
+}
+
+// Close closes the connections of the migrator.
+func (m *Migrator) Close() error {
+	m.conn.Close()
+	srcErr, dbErr := m.m.Close()
+	if srcErr != nil {
+		return srcErr
+	}
+	return dbErr
+}
+
+// locked runs fn holding the advisory lock, it returns ErrMigrationLocked if
+// it isn't released in lockTimeout.
+func (m *Migrator) locked(ctx context.Context, fn func() error) error {
+	// lock_timeout also applies to advisory locks
+	_, err := m.conn.ExecContext(ctx, fmt.Sprintf("SET lock_timeout = %d", m.lockTimeout.Milliseconds()))
+	if err != nil {
+		return fmt.Errorf("unable to lock migrations: %w", err)
+	}
+	_, err = m.conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext($1))", migrationLock)
+	if err != nil {
+		var perr *pq.Error
+		if errors.As(err, &perr) && perr.Code == "55P03" {
+			return ErrMigrationLocked
+		}
+		return fmt.Errorf("unable to lock migrations: %w", err)
+	}
+	defer m.conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock(hashtext($1))", migrationLock)
+
+	return fn()
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,7 @@
+DROP TABLE user_role;
+DROP TABLE refresh_token;
+DROP TABLE api_key;
+DROP TABLE user_audit;
+DROP FUNCTION user_audit_append_only;
+DROP TABLE users;
+DROP FUNCTION set_updated_at;
@@ -0,0 +1,93 @@
+-- The schema is the same as the one of mysql after 20261019140000_add_tenant.
+
+-- updated_at is set by a trigger, like ON UPDATE of mysql.
+CREATE FUNCTION set_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+  NEW.updated_at = now();
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- user is a reserved word in postgres.
+CREATE TABLE users (
+  id CHAR(20) NOT NULL,
+  tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+  first_name VARCHAR(100) NOT NULL,
+  last_name VARCHAR(100) NOT NULL,
+  nickname VARCHAR(100) NOT NULL,
+  password VARCHAR(255) NOT NULL,
+  email VARCHAR(255) NOT NULL,
+  country CHAR(2) NOT NULL,
+  created_at TIMESTAMPTZ(6) NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ(6) NOT NULL DEFAULT now(),
+  removed_at TIMESTAMPTZ(6) NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT users_tenant_email UNIQUE (tenant_id, email)
+);
+
+CREATE TRIGGER users_updated_at BEFORE UPDATE ON users
+  FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE user_audit (
+  id CHAR(20) NOT NULL,
+  user_id CHAR(20) NOT NULL,
+  action VARCHAR(50) NOT NULL,
+  actor VARCHAR(255) NOT NULL,
+  request_id VARCHAR(255) NOT NULL DEFAULT '',
+  changes JSONB NOT NULL,
+  created_at TIMESTAMPTZ(6) NOT NULL DEFAULT now(),
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX user_audit_user_id_created_at ON user_audit (user_id, created_at);
+
+-- The audit log is append-only.
+CREATE FUNCTION user_audit_append_only() RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'user_audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER user_audit_no_update BEFORE UPDATE ON user_audit
+  FOR EACH ROW EXECUTE FUNCTION user_audit_append_only();
+
+CREATE TRIGGER user_audit_no_delete BEFORE DELETE ON user_audit
+  FOR EACH ROW EXECUTE FUNCTION user_audit_append_only();
+
+CREATE TABLE api_key (
+  id CHAR(20) NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  key_hash CHAR(64) NOT NULL,
+  scopes JSONB NOT NULL,
+  tenant_id VARCHAR(64) NULL,
+  expires_at TIMESTAMPTZ NULL,
+  last_used_at TIMESTAMPTZ NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  revoked_at TIMESTAMPTZ NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT api_key_key_hash UNIQUE (key_hash)
+);
+
+CREATE TABLE refresh_token (
+  id CHAR(20) NOT NULL,
+  family_id CHAR(20) NOT NULL,
+  user_id CHAR(20) NOT NULL,
+  tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+  token_hash CHAR(64) NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  used_at TIMESTAMPTZ NULL,
+  revoked_at TIMESTAMPTZ NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT refresh_token_token_hash UNIQUE (token_hash)
+);
+
+CREATE INDEX refresh_token_family_id ON refresh_token (family_id);
+
+CREATE TABLE user_role (
+  user_id CHAR(20) NOT NULL,
+  role VARCHAR(32) NOT NULL,
+  granted_by VARCHAR(255) NOT NULL,
+  granted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT user_role_pkey PRIMARY KEY (user_id, role)
+);
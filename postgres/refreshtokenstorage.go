@@ -0,0 +1,13 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/guilherme-santos/user/sqlstorage"
+)
+
+// NewRefreshTokenStorage returns the storage of the refresh tokens in the
+// refresh_token table.
+func NewRefreshTokenStorage(db *sql.DB) *sqlstorage.RefreshTokenStorage {
+	return sqlstorage.NewRefreshTokenStorage(sqlstorage.NewDB(db), Dialect)
+}
@@ -0,0 +1,13 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/guilherme-santos/user/sqlstorage"
+)
+
+// NewRoleStorage returns the storage of the roles granted to users in the
+// user_role table.
+func NewRoleStorage(db *sql.DB) *sqlstorage.RoleStorage {
+	return sqlstorage.NewRoleStorage(sqlstorage.NewDB(db), Dialect)
+}
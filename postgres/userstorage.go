@@ -0,0 +1,12 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/guilherme-santos/user/sqlstorage"
+)
+
+// NewUserStorage returns the storage of the users in the users table.
+func NewUserStorage(db *sql.DB) *sqlstorage.UserStorage {
+	return sqlstorage.NewUserStorage(sqlstorage.NewDB(db), Dialect)
+}
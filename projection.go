@@ -0,0 +1,97 @@
+package user
+
+import "context"
+
+// Projection is the set of fields of User, named as in ExportFields, which
+// can be shown to a less-trusted party. A nil Projection allows all fields.
+type Projection []string
+
+// PublicProjection contains the fields which aren't personal data, e.g. for
+// partner services.
+var PublicProjection = Projection{"id", "nickname", "country"}
+
+// Allows returns whether field is part of p.
+func (p Projection) Allows(field string) bool {
+	if p == nil {
+		return true
+	}
+	for _, f := range p {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns a copy of u with only the fields of p, all others are left
+// empty. u is returned as it's when p is nil.
+func (p Projection) Apply(u *User) *User {
+	if p == nil || u == nil {
+		return u
+	}
+	pu := new(User)
+	for _, f := range p {
+		if project, ok := projectionFields[f]; ok {
+			project(pu, u)
+		}
+	}
+	return pu
+}
+
+var projectionFields = map[string]func(dst, src *User){
+	"id":         func(dst, src *User) { dst.ID = src.ID },
+	"first_name": func(dst, src *User) { dst.FirstName = src.FirstName },
+	"last_name":  func(dst, src *User) { dst.LastName = src.LastName },
+	"nickname":   func(dst, src *User) { dst.Nickname = src.Nickname },
+	"email":      func(dst, src *User) { dst.Email = src.Email },
+	"country":    func(dst, src *User) { dst.Country = src.Country },
+	"created_at": func(dst, src *User) { dst.CreatedAt = src.CreatedAt },
+	"updated_at": func(dst, src *User) { dst.UpdatedAt = src.UpdatedAt },
+}
+
+// ProjectedEventService publishes the events of the decorated service with
+// only the fields of its projection, for brokers with less-trusted
+// subscribers. The tenant is always kept, so subscribers can route them.
+type ProjectedEventService struct {
+	eventsvc   EventService
+	projection Projection
+}
+
+// Make sure ProjectedEventService implements EventService
+var _ EventService = &ProjectedEventService{}
+
+func NewProjectedEventService(eventsvc EventService, p Projection) *ProjectedEventService {
+	return &ProjectedEventService{
+		eventsvc:   eventsvc,
+		projection: p,
+	}
+}
+
+func (s ProjectedEventService) UserCreated(ctx context.Context, u *User) error {
+	return s.eventsvc.UserCreated(ctx, s.apply(u))
+}
+
+func (s ProjectedEventService) UserUpdated(ctx context.Context, u *User) error {
+	return s.eventsvc.UserUpdated(ctx, s.apply(u))
+}
+
+func (s ProjectedEventService) UserDeleted(ctx context.Context, u *User) error {
+	return s.eventsvc.UserDeleted(ctx, s.apply(u))
+}
+
+func (s ProjectedEventService) apply(u *User) *User {
+	pu := s.projection.Apply(u)
+	if pu != u {
+		pu.TenantID = u.TenantID
+	}
+	return pu
+}
+
+// RoleGranted doesn't have personal data, so it's published as it's.
+func (s ProjectedEventService) RoleGranted(ctx context.Context, ra *RoleAssignment) error {
+	return s.eventsvc.RoleGranted(ctx, ra)
+}
+
+func (s ProjectedEventService) RoleRevoked(ctx context.Context, ra *RoleAssignment) error {
+	return s.eventsvc.RoleRevoked(ctx, ra)
+}
@@ -0,0 +1,42 @@
+package user_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectionApply(t *testing.T) {
+	u := &user.User{
+		ID:        "user-1",
+		FirstName: "John",
+		LastName:  "Doe",
+		Nickname:  "johndoe",
+		Email:     "john@doe.com",
+		Country:   "DE",
+	}
+
+	assert.Same(t, u, user.Projection(nil).Apply(u))
+	assert.Equal(t, &user.User{ID: "user-1", Nickname: "johndoe", Country: "DE"}, user.PublicProjection.Apply(u))
+	// u isn't changed
+	assert.Equal(t, "john@doe.com", u.Email)
+}
+
+func TestProjectedEventService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	u := &user.User{ID: "user-1", FirstName: "John", Nickname: "johndoe", Email: "john@doe.com", Country: "DE", TenantID: "brand-1"}
+
+	eventsvc := mock.NewEventService(ctrl)
+	eventsvc.EXPECT().UserCreated(ctx, &user.User{ID: "user-1", Nickname: "johndoe", Country: "DE", TenantID: "brand-1"}).Return(nil)
+
+	svc := user.NewProjectedEventService(eventsvc, user.PublicProjection)
+	assert.NoError(t, svc.UserCreated(ctx, u))
+}
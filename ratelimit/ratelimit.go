@@ -0,0 +1,168 @@
+// Package ratelimit implements token bucket rate limiting, buckets are kept
+// in a pluggable Store so the limits can be shared between instances.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Burst requests at once, refilling the bucket at Rate tokens
+// per second. A zero Limit disables the rate limiting.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Every returns a Limit which allows n requests every period.
+func Every(n int, period time.Duration) Limit {
+	return Limit{
+		Rate:  float64(n) / period.Seconds(),
+		Burst: n,
+	}
+}
+
+// ParseLimit parses limits in the format n/period, e.g. 10/s, 100/m or 1000/1h.
+func ParseLimit(s string) (Limit, error) {
+	n, period, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid limit %q: expected n/period", s)
+	}
+	burst, err := strconv.Atoi(n)
+	if err != nil || burst < 0 {
+		return Limit{}, fmt.Errorf("invalid limit %q: invalid number of requests", s)
+	}
+	if period != "" && (period[0] < '0' || period[0] > '9') {
+		period = "1" + period
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid limit %q: invalid period", s)
+	}
+	return Every(burst, d), nil
+}
+
+// Decode implements envconfig.Decoder, empty value disables the limit.
+func (l *Limit) Decode(value string) error {
+	if strings.TrimSpace(value) == "" {
+		*l = Limit{}
+		return nil
+	}
+	limit, err := ParseLimit(value)
+	if err != nil {
+		return err
+	}
+	*l = limit
+	return nil
+}
+
+// String returns the limit in the format of ParseLimit, empty if disabled.
+func (l Limit) String() string {
+	if l.Disabled() {
+		return ""
+	}
+	period := seconds(float64(l.Burst) / l.Rate).Round(time.Millisecond)
+	switch period {
+	case time.Second:
+		return strconv.Itoa(l.Burst) + "/s"
+	case time.Minute:
+		return strconv.Itoa(l.Burst) + "/m"
+	case time.Hour:
+		return strconv.Itoa(l.Burst) + "/h"
+	}
+	return strconv.Itoa(l.Burst) + "/" + period.String()
+}
+
+func (l Limit) Disabled() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+// Limits are limits by name, e.g. the route.
+type Limits map[string]Limit
+
+// Decode implements envconfig.Decoder parsing name=limit pairs separated by
+// comma, e.g. "POST /v1/users=10/m,GET /v1/users=100/s".
+func (ls *Limits) Decode(value string) error {
+	limits := make(Limits)
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		i := strings.LastIndex(pair, "=")
+		if i < 0 {
+			return fmt.Errorf("invalid limit %q: expected name=limit", pair)
+		}
+		limit, err := ParseLimit(pair[i+1:])
+		if err != nil {
+			return err
+		}
+		limits[strings.TrimSpace(pair[:i])] = limit
+	}
+	*ls = limits
+	return nil
+}
+
+// String returns the limits in the format of Decode, sorted by name.
+func (ls Limits) String() string {
+	names := make([]string, 0, len(ls))
+	for name := range ls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + ls[name].String()
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Result is the state of a bucket after taking a token from it.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is the time until a token is available, zero when allowed.
+	RetryAfter time.Duration
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+}
+
+// Store keeps the state of the buckets.
+type Store interface {
+	// Take takes a token from the bucket of key.
+	Take(_ context.Context, key string, limit Limit) (*Result, error)
+}
+
+// take refills the bucket with tokens available since elapsed and takes a
+// token from it, returning the tokens left.
+func take(limit Limit, tokens float64, elapsed time.Duration) (float64, bool) {
+	tokens = math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+	if tokens < 1 {
+		return tokens, false
+	}
+	return tokens - 1, true
+}
+
+// newResult returns the Result of a bucket with tokens left.
+func newResult(limit Limit, tokens float64, allowed bool) *Result {
+	res := &Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(tokens),
+		Reset:     seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
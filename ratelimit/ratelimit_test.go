@@ -0,0 +1,102 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guilherme-santos/user/ratelimit"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected ratelimit.Limit
+		err      bool
+	}{
+		{value: "10/s", expected: ratelimit.Limit{Rate: 10, Burst: 10}},
+		{value: "120/m", expected: ratelimit.Limit{Rate: 2, Burst: 120}},
+		{value: "30/30s", expected: ratelimit.Limit{Rate: 1, Burst: 30}},
+		{value: "10", err: true},
+		{value: "x/s", err: true},
+		{value: "10/x", err: true},
+	}
+
+	for _, tt := range tests {
+		limit, err := ratelimit.ParseLimit(tt.value)
+		if tt.err {
+			assert.Error(t, err, tt.value)
+			continue
+		}
+		assert.NoError(t, err, tt.value)
+		assert.Equal(t, tt.expected, limit, tt.value)
+	}
+}
+
+func TestLimitString(t *testing.T) {
+	assert.Equal(t, "10/s", ratelimit.Every(10, time.Second).String())
+	assert.Equal(t, "120/m", ratelimit.Every(120, time.Minute).String())
+	assert.Equal(t, "30/30s", ratelimit.Every(30, 30*time.Second).String())
+	assert.Equal(t, "", ratelimit.Limit{}.String())
+
+	limits := ratelimit.Limits{
+		"POST /v1/users:import": ratelimit.Every(1, time.Second),
+		"POST /v1/users":        ratelimit.Every(10, time.Minute),
+	}
+	assert.Equal(t, "POST /v1/users=10/m,POST /v1/users:import=1/s", limits.String())
+}
+
+func TestLimitsDecode(t *testing.T) {
+	var limits ratelimit.Limits
+	err := limits.Decode("POST /v1/users=10/m, POST /v1/users:import=1/s")
+	assert.NoError(t, err)
+	assert.Equal(t, ratelimit.Limits{
+		"POST /v1/users":        ratelimit.Every(10, time.Minute),
+		"POST /v1/users:import": ratelimit.Every(1, time.Second),
+	}, limits)
+}
+
+func TestStores(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	stores := map[string]ratelimit.Store{
+		"memory": ratelimit.NewMemoryStore(),
+		"redis":  ratelimit.NewRedisStore(client, "ratelimit:"),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			limit := ratelimit.Every(2, time.Hour)
+
+			res, err := store.Take(ctx, "ip:127.0.0.1", limit)
+			assert.NoError(t, err)
+			assert.True(t, res.Allowed)
+			assert.Equal(t, 2, res.Limit)
+			assert.Equal(t, 1, res.Remaining)
+
+			res, err = store.Take(ctx, "ip:127.0.0.1", limit)
+			assert.NoError(t, err)
+			assert.True(t, res.Allowed)
+			assert.Equal(t, 0, res.Remaining)
+
+			res, err = store.Take(ctx, "ip:127.0.0.1", limit)
+			assert.NoError(t, err)
+			assert.False(t, res.Allowed)
+			assert.Equal(t, 0, res.Remaining)
+			// a token is refilled every 30 minutes
+			assert.InDelta(t, 30*time.Minute, res.RetryAfter, float64(time.Second))
+
+			// other keys have their own bucket
+			res, err = store.Take(ctx, "ip:127.0.0.2", limit)
+			assert.NoError(t, err)
+			assert.True(t, res.Allowed)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript is the same algorithm as take, run atomically in redis. Tokens
+// are returned as string because redis truncates lua numbers.
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) / 1000 * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst / rate * 1000))
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore keeps the buckets in redis, or any redis compatible server which
+// supports lua scripts, so limits are shared between instances.
+type RedisStore struct {
+	client redis.Scripter
+	prefix string
+}
+
+// Make sure RedisStore implements Store
+var _ Store = &RedisStore{}
+
+// NewRedisStore returns a store which prefixes all keys with prefix.
+func NewRedisStore(client redis.Scripter, prefix string) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s RedisStore) Take(ctx context.Context, key string, limit Limit) (*Result, error) {
+	now := time.Now().UnixMilli()
+	args := []interface{}{
+		strconv.FormatFloat(limit.Rate, 'f', -1, 64),
+		limit.Burst,
+		now,
+	}
+	values, err := takeScript.Run(ctx, s.client, []string{s.prefix + key}, args...).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, _ := values[0].(int64)
+	left, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(left, 64)
+	if err != nil {
+		return nil, err
+	}
+	tokens = math.Max(0, tokens)
+	return newResult(limit, tokens, allowed == 1), nil
+}
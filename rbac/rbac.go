@@ -0,0 +1,58 @@
+// Package rbac enforces the permission matrix of the roles granted to users
+// by decorating the services. Only requests authenticated by an access token
+// are checked, the ones authenticated by API keys are limited by their scopes.
+package rbac
+
+import (
+	"context"
+
+	"github.com/guilherme-santos/user"
+)
+
+var errNotSupported = &user.Err{Type: user.Unknown, Code: "not_supported", Message: "operation not supported by the service"}
+
+// authorizer checks the permissions of the user performing the request.
+type authorizer struct {
+	roles user.RoleStorage
+}
+
+// principal returns the id of the user performing the request, empty when it
+// wasn't authenticated by an access token.
+func principal(ctx context.Context) string {
+	claims := user.TokenClaimsFrom(ctx)
+	if claims == nil {
+		return ""
+	}
+	return claims.Subject
+}
+
+// can returns whether the user performing the request has p, requests not
+// performed by a user always can.
+func (a authorizer) can(ctx context.Context, p user.Permission) (bool, error) {
+	id := principal(ctx)
+	if id == "" {
+		return true, nil
+	}
+	assignments, err := a.roles.Roles(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	roles := make([]string, len(assignments))
+	for i, ra := range assignments {
+		roles[i] = ra.Role
+	}
+	return user.HasPermission(roles, p), nil
+}
+
+// authorize returns user.ErrForbidden when the user performing the request
+// doesn't have p.
+func (a authorizer) authorize(ctx context.Context, p user.Permission) error {
+	ok, err := a.can(ctx, p)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return user.ErrForbidden
+	}
+	return nil
+}
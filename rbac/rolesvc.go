@@ -0,0 +1,51 @@
+package rbac
+
+import (
+	"context"
+
+	"github.com/guilherme-santos/user"
+)
+
+// RoleService checks the permissions of the user performing the request
+// before calling the decorated service, only superadmins can grant roles.
+type RoleService struct {
+	authorizer
+	svc user.RoleService
+}
+
+// Make sure RoleService implements user.RoleService
+var _ user.RoleService = &RoleService{}
+
+func NewRoleService(svc user.RoleService, roles user.RoleStorage) *RoleService {
+	return &RoleService{
+		authorizer: authorizer{roles: roles},
+		svc:        svc,
+	}
+}
+
+// ListRoles lets users read their own roles.
+func (s RoleService) ListRoles(ctx context.Context, userID string) ([]*user.RoleAssignment, error) {
+	if userID != principal(ctx) {
+		err := s.authorize(ctx, user.PermReadUsers)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.svc.ListRoles(ctx, userID)
+}
+
+func (s RoleService) GrantRole(ctx context.Context, userID, role string) (*user.RoleAssignment, error) {
+	err := s.authorize(ctx, user.PermGrantRoles)
+	if err != nil {
+		return nil, err
+	}
+	return s.svc.GrantRole(ctx, userID, role)
+}
+
+func (s RoleService) RevokeRole(ctx context.Context, userID, role string) error {
+	err := s.authorize(ctx, user.PermGrantRoles)
+	if err != nil {
+		return err
+	}
+	return s.svc.RevokeRole(ctx, userID, role)
+}
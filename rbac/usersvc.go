@@ -0,0 +1,131 @@
+package rbac
+
+import (
+	"context"
+
+	"github.com/guilherme-santos/user"
+)
+
+// UserService checks the permissions of the user performing the request
+// before calling the decorated service. Users can always read and update
+// their own record, the access tokens of deleted users are rejected by
+// user.TokenService.
+type UserService struct {
+	authorizer
+	svc user.Service
+}
+
+// Make sure UserService implements all services
+var (
+	_ user.Service       = &UserService{}
+	_ user.ImportService = &UserService{}
+	_ user.ExportService = &UserService{}
+	_ user.AuditService  = &UserService{}
+)
+
+func NewUserService(svc user.Service, roles user.RoleStorage) *UserService {
+	return &UserService{
+		authorizer: authorizer{roles: roles},
+		svc:        svc,
+	}
+}
+
+func (s UserService) Create(ctx context.Context, u *user.User) error {
+	err := s.authorize(ctx, user.PermCreateUsers)
+	if err != nil {
+		return err
+	}
+	return s.svc.Create(ctx, u)
+}
+
+// Update requires user.PermUpdateCredentials in addition to
+// user.PermUpdateUsers when the e-mail or the password is changed.
+func (s UserService) Update(ctx context.Context, u *user.User) error {
+	if u.ID == principal(ctx) {
+		return s.svc.Update(ctx, u)
+	}
+
+	err := s.authorize(ctx, user.PermUpdateUsers)
+	if err != nil {
+		return err
+	}
+	ok, err := s.can(ctx, user.PermUpdateCredentials)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if u.Password != "" {
+			return user.ErrForbidden
+		}
+		current, err := s.svc.Get(ctx, u.ID)
+		if err != nil {
+			return err
+		}
+		if current.Email != u.Email {
+			return user.ErrForbidden
+		}
+	}
+	return s.svc.Update(ctx, u)
+}
+
+func (s UserService) Delete(ctx context.Context, id string) error {
+	err := s.authorize(ctx, user.PermDeleteUsers)
+	if err != nil {
+		return err
+	}
+	return s.svc.Delete(ctx, id)
+}
+
+func (s UserService) Get(ctx context.Context, id string) (*user.User, error) {
+	if id != principal(ctx) {
+		err := s.authorize(ctx, user.PermReadUsers)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.svc.Get(ctx, id)
+}
+
+func (s UserService) List(ctx context.Context, opts *user.ListOptions) (*user.ListResponse, error) {
+	err := s.authorize(ctx, user.PermReadUsers)
+	if err != nil {
+		return nil, err
+	}
+	return s.svc.List(ctx, opts)
+}
+
+func (s UserService) Import(ctx context.Context, r user.ImportReader, opts *user.ImportOptions, fn func(*user.ImportResult)) (*user.ImportSummary, error) {
+	is, ok := s.svc.(user.ImportService)
+	if !ok {
+		return nil, errNotSupported
+	}
+	err := s.authorize(ctx, user.PermCreateUsers)
+	if err != nil {
+		return nil, err
+	}
+	return is.Import(ctx, r, opts, fn)
+}
+
+func (s UserService) Export(ctx context.Context, opts *user.ListOptions, fn func(*user.User) error) error {
+	es, ok := s.svc.(user.ExportService)
+	if !ok {
+		return errNotSupported
+	}
+	err := s.authorize(ctx, user.PermExportUsers)
+	if err != nil {
+		return err
+	}
+	return es.Export(ctx, opts, fn)
+}
+
+func (s UserService) AuditLog(ctx context.Context, userID string, opts *user.AuditListOptions) (*user.AuditListResponse, error) {
+	as, ok := s.svc.(user.AuditService)
+	if !ok {
+		return nil, errNotSupported
+	}
+	err := s.authorize(ctx, user.PermReadAuditLog)
+	if err != nil {
+		return nil, err
+	}
+	return as.AuditLog(ctx, userID, opts)
+}
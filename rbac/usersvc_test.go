@@ -0,0 +1,136 @@
+package rbac_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/mock"
+	"github.com/guilherme-santos/user/rbac"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// asUser returns a context authenticated by an access token of id.
+func asUser(id string) context.Context {
+	return user.SetTokenClaims(context.Background(), &user.TokenClaims{Subject: id})
+}
+
+func roles(names ...string) []*user.RoleAssignment {
+	roles := make([]*user.RoleAssignment, len(names))
+	for i, n := range names {
+		roles[i] = &user.RoleAssignment{Role: n}
+	}
+	return roles
+}
+
+func TestUserServiceUpdate(t *testing.T) {
+	current := &user.User{ID: "user-1", Email: "john@doe.com"}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		roles  []*user.RoleAssignment
+		update *user.User
+		err    error
+	}{
+		{
+			name:   "api key",
+			ctx:    context.Background(),
+			update: &user.User{ID: "user-1", Email: "other@doe.com"},
+		},
+		{
+			name:   "own record",
+			ctx:    asUser("user-1"),
+			update: &user.User{ID: "user-1", Email: "other@doe.com"},
+		},
+		{
+			name:   "support profile",
+			ctx:    asUser("support-1"),
+			roles:  roles(user.RoleSupport),
+			update: &user.User{ID: "user-1", Email: "john@doe.com", Nickname: "johnny"},
+		},
+		{
+			name:   "support email",
+			ctx:    asUser("support-1"),
+			roles:  roles(user.RoleSupport),
+			update: &user.User{ID: "user-1", Email: "other@doe.com"},
+			err:    user.ErrForbidden,
+		},
+		{
+			name:   "support password",
+			ctx:    asUser("support-1"),
+			roles:  roles(user.RoleSupport),
+			update: &user.User{ID: "user-1", Email: "john@doe.com", Password: "secret"},
+			err:    user.ErrForbidden,
+		},
+		{
+			name:   "admin email",
+			ctx:    asUser("admin-1"),
+			roles:  roles(user.RoleAdmin),
+			update: &user.User{ID: "user-1", Email: "other@doe.com"},
+		},
+		{
+			name:   "no role",
+			ctx:    asUser("user-2"),
+			roles:  roles(),
+			update: &user.User{ID: "user-1", Email: "john@doe.com"},
+			err:    user.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			storage := mock.NewRoleStorage(ctrl)
+			if tt.roles != nil {
+				storage.EXPECT().Roles(gomock.Any(), gomock.Any()).Return(tt.roles, nil).AnyTimes()
+			}
+			svc := mock.NewUserService(ctrl)
+			svc.EXPECT().Get(gomock.Any(), "user-1").Return(current, nil).AnyTimes()
+			if tt.err == nil {
+				svc.EXPECT().Update(gomock.Any(), tt.update).Return(nil)
+			}
+
+			err := rbac.NewUserService(svc, storage).Update(tt.ctx, tt.update)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestUserServiceDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mock.NewRoleStorage(ctrl)
+	storage.EXPECT().Roles(gomock.Any(), "support-1").Return(roles(user.RoleSupport), nil)
+	storage.EXPECT().Roles(gomock.Any(), "admin-1").Return(roles(user.RoleSupport, user.RoleAdmin), nil)
+
+	svc := mock.NewUserService(ctrl)
+	svc.EXPECT().Delete(gomock.Any(), "user-1").Return(nil)
+
+	rbacsvc := rbac.NewUserService(svc, storage)
+	assert.Equal(t, user.ErrForbidden, rbacsvc.Delete(asUser("support-1"), "user-1"))
+	assert.NoError(t, rbacsvc.Delete(asUser("admin-1"), "user-1"))
+}
+
+func TestRoleServiceGrantRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := mock.NewRoleStorage(ctrl)
+	storage.EXPECT().Roles(gomock.Any(), "admin-1").Return(roles(user.RoleAdmin), nil)
+	storage.EXPECT().Roles(gomock.Any(), "superadmin-1").Return(roles(user.RoleSuperadmin), nil)
+
+	svc := mock.NewRoleService(ctrl)
+	svc.EXPECT().GrantRole(gomock.Any(), "user-1", user.RoleAdmin).Return(&user.RoleAssignment{}, nil)
+
+	rbacsvc := rbac.NewRoleService(svc, storage)
+	_, err := rbacsvc.GrantRole(asUser("admin-1"), "user-1", user.RoleAdmin)
+	assert.Equal(t, user.ErrForbidden, err)
+	_, err = rbacsvc.GrantRole(asUser("superadmin-1"), "user-1", user.RoleAdmin)
+	assert.NoError(t, err)
+}
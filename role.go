@@ -0,0 +1,242 @@
+package user
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Roles which can be granted to users.
+const (
+	RoleSupport    = "support"
+	RoleAdmin      = "admin"
+	RoleSuperadmin = "superadmin"
+)
+
+// Roles contains all valid roles.
+var Roles = []string{RoleSupport, RoleAdmin, RoleSuperadmin}
+
+// Permission is an operation on user records which a role may allow.
+type Permission string
+
+const (
+	PermReadUsers   Permission = "users.read"
+	PermCreateUsers Permission = "users.create"
+	// PermUpdateUsers allows changing the profile fields: names, nickname
+	// and country.
+	PermUpdateUsers Permission = "users.update"
+	// PermUpdateCredentials allows changing the e-mail and the password.
+	PermUpdateCredentials Permission = "users.update_credentials"
+	PermDeleteUsers       Permission = "users.delete"
+	PermExportUsers       Permission = "users.export"
+	PermReadAuditLog      Permission = "users.audit"
+	PermGrantRoles        Permission = "roles.grant"
+)
+
+// rolePermissions is the permission matrix, each role has all permissions of
+// the previous one.
+var rolePermissions = map[string][]Permission{
+	RoleSupport: {
+		PermReadUsers,
+		PermUpdateUsers,
+		PermReadAuditLog,
+	},
+	RoleAdmin: {
+		PermReadUsers,
+		PermUpdateUsers,
+		PermReadAuditLog,
+		PermCreateUsers,
+		PermUpdateCredentials,
+		PermDeleteUsers,
+		PermExportUsers,
+	},
+	RoleSuperadmin: {
+		PermReadUsers,
+		PermUpdateUsers,
+		PermReadAuditLog,
+		PermCreateUsers,
+		PermUpdateCredentials,
+		PermDeleteUsers,
+		PermExportUsers,
+		PermGrantRoles,
+	},
+}
+
+// HasPermission returns whether any of roles allows p.
+func HasPermission(roles []string, p Permission) bool {
+	for _, r := range roles {
+		for _, rp := range rolePermissions[r] {
+			if rp == p {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+const (
+	AuditRoleGranted = "role.granted"
+	AuditRoleRevoked = "role.revoked"
+)
+
+var (
+	ErrForbidden    = &Err{Type: PermissionDenied, Code: "forbidden", Message: "User doesn't have the required permission"}
+	ErrRoleNotFound = &Err{Type: NotFound, Code: "role_not_found", Message: "role not granted to the user"}
+	ErrRoleGranted  = &Err{Type: InvalidArgument, Code: "role_already_granted", Message: "Role is already granted to the user"}
+)
+
+//go:generate mockgen -package mock -mock_names RoleService=RoleService -destination mock/rolesvc.go github.com/guilherme-santos/user RoleService
+
+// RoleService is an interface which implements the management of the roles
+// assigned to users.
+type RoleService interface {
+	// ListRoles returns the roles granted to the user.
+	ListRoles(_ context.Context, userID string) ([]*RoleAssignment, error)
+	GrantRole(_ context.Context, userID, role string) (*RoleAssignment, error)
+	RevokeRole(_ context.Context, userID, role string) error
+}
+
+//go:generate mockgen -package mock -mock_names RoleStorage=RoleStorage -destination mock/rolestorage.go github.com/guilherme-santos/user RoleStorage
+
+// RoleStorage is an interface which implements the storage of the roles
+// assigned to users.
+type RoleStorage interface {
+	// Roles returns the roles granted to the user, empty if there's none.
+	Roles(_ context.Context, userID string) ([]*RoleAssignment, error)
+	// Grant returns ErrRoleGranted if the user already has the role.
+	Grant(context.Context, *RoleAssignment) error
+	// Revoke returns ErrRoleNotFound if the user doesn't have the role.
+	Revoke(_ context.Context, userID, role string) error
+}
+
+// RoleAssignment is a role granted to a user.
+type RoleAssignment struct {
+	UserID    string    `json:"user_id"`
+	Role      string    `json:"role"`
+	GrantedBy string    `json:"granted_by"`
+	GrantedAt time.Time `json:"granted_at"`
+	// TenantID is only set in the events.
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+func validRole(role string) error {
+	if role == "" {
+		return NewMissingFieldError("role")
+	}
+	for _, r := range Roles {
+		if r == role {
+			return nil
+		}
+	}
+	return &FieldError{
+		Err: Error{
+			Type:    InvalidArgument,
+			Code:    "invalid_role",
+			Message: "Role must be one of " + strings.Join(Roles, ", "),
+		},
+		Field: "role",
+	}
+}
+
+type RoleServiceImpl struct {
+	storage  RoleStorage
+	users    Storage
+	eventsvc EventService
+	audit    AuditStorage
+}
+
+// Make sure RoleServiceImpl implements RoleService
+var _ RoleService = &RoleServiceImpl{}
+
+func NewRoleService(storage RoleStorage, users Storage, eventsvc EventService, audit AuditStorage) *RoleServiceImpl {
+	return &RoleServiceImpl{
+		storage:  storage,
+		users:    users,
+		eventsvc: eventsvc,
+		audit:    audit,
+	}
+}
+
+func (s RoleServiceImpl) ListRoles(ctx context.Context, userID string) ([]*RoleAssignment, error) {
+	// Make sure the user exists
+	_, err := s.users.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.storage.Roles(ctx, userID)
+}
+
+// GrantRole grants role to the user, records it in the audit log and publish
+// a role.granted event to our message broker.
+func (s RoleServiceImpl) GrantRole(ctx context.Context, userID, role string) (*RoleAssignment, error) {
+	ctx = withUserID(ctx, userID)
+	err := validRole(role)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.users.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ra := &RoleAssignment{
+		UserID:    userID,
+		Role:      role,
+		GrantedBy: Actor(ctx),
+		GrantedAt: time.Now().UTC().Truncate(time.Second),
+		TenantID:  Tenant(ctx),
+	}
+	if ra.GrantedBy == "" {
+		ra.GrantedBy = AnonymousActor
+	}
+	err = s.storage.Grant(ctx, ra)
+	if err != nil {
+		return nil, err
+	}
+	err = s.audit.Append(ctx, newRoleAuditEntry(ctx, AuditRoleGranted, ra))
+	if err != nil {
+		return nil, err
+	}
+	err = s.eventsvc.RoleGranted(ctx, ra)
+	if err != nil {
+		return nil, err
+	}
+	return ra, nil
+}
+
+// RevokeRole revokes role from the user, records it in the audit log and
+// publish a role.revoked event to our message broker.
+func (s RoleServiceImpl) RevokeRole(ctx context.Context, userID, role string) error {
+	ctx = withUserID(ctx, userID)
+	err := validRole(role)
+	if err != nil {
+		return err
+	}
+	// Make sure the user exists, users of other tenants aren't found
+	_, err = s.users.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.Revoke(ctx, userID, role)
+	if err != nil {
+		return err
+	}
+	ra := &RoleAssignment{UserID: userID, Role: role, TenantID: Tenant(ctx)}
+	err = s.audit.Append(ctx, newRoleAuditEntry(ctx, AuditRoleRevoked, ra))
+	if err != nil {
+		return err
+	}
+	return s.eventsvc.RoleRevoked(ctx, ra)
+}
+
+func newRoleAuditEntry(ctx context.Context, action string, ra *RoleAssignment) *AuditEntry {
+	e := NewAuditEntry(ctx, action, nil, nil)
+	e.UserID = ra.UserID
+	change := AuditChange{After: ra.Role}
+	if action == AuditRoleRevoked {
+		change = AuditChange{Before: ra.Role}
+	}
+	e.Changes["role"] = change
+	return e
+}
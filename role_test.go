@@ -0,0 +1,98 @@
+package user_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasPermission(t *testing.T) {
+	assert.True(t, user.HasPermission([]string{user.RoleSupport}, user.PermUpdateUsers))
+	assert.False(t, user.HasPermission([]string{user.RoleSupport}, user.PermUpdateCredentials))
+	assert.False(t, user.HasPermission([]string{user.RoleSupport}, user.PermDeleteUsers))
+	assert.True(t, user.HasPermission([]string{user.RoleSupport, user.RoleAdmin}, user.PermDeleteUsers))
+	assert.False(t, user.HasPermission([]string{user.RoleAdmin}, user.PermGrantRoles))
+	assert.True(t, user.HasPermission([]string{user.RoleSuperadmin}, user.PermGrantRoles))
+	assert.False(t, user.HasPermission(nil, user.PermReadUsers))
+}
+
+func TestRoleServiceGrantRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := user.SetActor(context.Background(), "apikey:backoffice")
+
+	users := mock.NewUserStorage(ctrl)
+	users.EXPECT().Get(gomock.Any(), "user-1").Return(&user.User{ID: "user-1"}, nil)
+
+	storage := mock.NewRoleStorage(ctrl)
+	storage.EXPECT().
+		Grant(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, ra *user.RoleAssignment) error {
+			assert.Equal(t, "user-1", ra.UserID)
+			assert.Equal(t, user.RoleSupport, ra.Role)
+			assert.Equal(t, "apikey:backoffice", ra.GrantedBy)
+			return nil
+		})
+
+	audit := mock.NewAuditStorage(ctrl)
+	audit.EXPECT().
+		Append(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, e *user.AuditEntry) error {
+			assert.Equal(t, user.AuditRoleGranted, e.Action)
+			assert.Equal(t, "user-1", e.UserID)
+			assert.Equal(t, map[string]user.AuditChange{"role": {After: user.RoleSupport}}, e.Changes)
+			return nil
+		})
+
+	eventsvc := mock.NewEventService(ctrl)
+	eventsvc.EXPECT().RoleGranted(gomock.Any(), gomock.Any()).Return(nil)
+
+	svc := user.NewRoleService(storage, users, eventsvc, audit)
+	ra, err := svc.GrantRole(ctx, "user-1", user.RoleSupport)
+	assert.NoError(t, err)
+	assert.Equal(t, user.RoleSupport, ra.Role)
+
+	// Roles are validated
+	_, err = svc.GrantRole(ctx, "user-1", "root")
+	if ferr, ok := err.(*user.FieldError); assert.True(t, ok) {
+		assert.Equal(t, "invalid_role", ferr.Code)
+	}
+}
+
+func TestRoleServiceRevokeRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	storage := mock.NewRoleStorage(ctrl)
+	storage.EXPECT().Revoke(gomock.Any(), "user-1", user.RoleAdmin).Return(nil)
+	storage.EXPECT().Revoke(gomock.Any(), "user-1", user.RoleSupport).Return(user.ErrRoleNotFound)
+
+	audit := mock.NewAuditStorage(ctrl)
+	audit.EXPECT().
+		Append(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, e *user.AuditEntry) error {
+			assert.Equal(t, user.AuditRoleRevoked, e.Action)
+			assert.Equal(t, map[string]user.AuditChange{"role": {Before: user.RoleAdmin}}, e.Changes)
+			return nil
+		})
+
+	eventsvc := mock.NewEventService(ctrl)
+	eventsvc.EXPECT().
+		RoleRevoked(gomock.Any(), &user.RoleAssignment{UserID: "user-1", Role: user.RoleAdmin, TenantID: user.DefaultTenant}).
+		Return(nil)
+
+	users := mock.NewUserStorage(ctrl)
+	users.EXPECT().Get(gomock.Any(), "user-1").Return(&user.User{ID: "user-1"}, nil).Times(2)
+
+	svc := user.NewRoleService(storage, users, eventsvc, audit)
+	assert.NoError(t, svc.RevokeRole(ctx, "user-1", user.RoleAdmin))
+	assert.Equal(t, user.ErrRoleNotFound, svc.RevokeRole(ctx, "user-1", user.RoleSupport))
+}
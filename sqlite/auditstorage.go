@@ -0,0 +1,13 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/guilherme-santos/user/sqlstorage"
+)
+
+// NewAuditStorage returns the storage of the audit log in the user_audit
+// table, which has triggers to reject any UPDATE or DELETE.
+func NewAuditStorage(db *sql.DB) *sqlstorage.AuditStorage {
+	return sqlstorage.NewAuditStorage(sqlstorage.NewDB(db), Dialect)
+}
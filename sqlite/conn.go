@@ -0,0 +1,71 @@
+// Package sqlite implements the storages of the service in SQLite, for
+// single-node deployments and development, they behave the same as the ones
+// of the mysql package. It uses a pure Go driver, so no cgo is needed.
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/guilherme-santos/user/sqlstorage"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// DSN returns the connection string of the database file at path. It's in WAL
+// mode, so reads don't block writes, and transactions lock the database for
+// writing when they begin, waiting up to 5 seconds for other writers.
+func DSN(path string) string {
+	return path + "?" + strings.Join([]string{
+		"_pragma=busy_timeout(5000)",
+		"_pragma=journal_mode(WAL)",
+		"_pragma=synchronous(NORMAL)",
+		"_txlock=immediate",
+		"_time_format=sqlite",
+	}, "&")
+}
+
+// NewConnection opens the database file at path, creating it if it doesn't
+// exist. Migrations aren't applied, see NewMigrator.
+func NewConnection(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", DSN(path))
+	if err != nil {
+		return nil, err
+	}
+	db.SetConnMaxLifetime(1 * time.Hour)
+	db.SetMaxIdleConns(2)
+	db.SetMaxOpenConns(10)
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Dialect is used by the storages of the sqlstorage package.
+var Dialect = sqlstorage.Dialect{
+	Placeholder:      sqlstorage.QuestionPlaceholder,
+	IsDuplicateError: IsDuplicateError,
+	UserTable:        "user",
+	UserEmailKey:     "user.tenant_id, user.email",
+	RoleKey:          "user_role.user_id, user_role.role",
+}
+
+// IsDuplicateError returns whether err is a violation of the unique
+// constraint of columns, e.g. "user.tenant_id, user.email".
+func IsDuplicateError(err error, columns string) bool {
+	var serr *sqlite.Error
+	if errors.As(err, &serr) {
+		switch serr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			// e.g. UNIQUE constraint failed: user.tenant_id, user.email (2067)
+			return strings.Contains(serr.Error(), "constraint failed: "+columns+" (")
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDB returns a migrated database in a temporary file.
+func newDB(t *testing.T) *sql.DB {
+	path := filepath.Join(t.TempDir(), "user.db")
+
+	m, err := NewMigrator(path)
+	require.NoError(t, err)
+	require.NoError(t, m.Up(context.Background()))
+	require.NoError(t, m.Close())
+
+	db, err := NewConnection(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestNewConnectionWAL(t *testing.T) {
+	db := newDB(t)
+
+	var mode string
+	require.NoError(t, db.QueryRow("PRAGMA journal_mode").Scan(&mode))
+	assert.Equal(t, "wal", mode)
+}
+
+func TestStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "user.db")
+	m, err := NewMigrator(path)
+	require.NoError(t, err)
+	defer m.Close()
+
+	status, err := m.Status()
+	require.NoError(t, err)
+	assert.True(t, status.Behind())
+
+	require.NoError(t, m.Up(context.Background()))
+	status, err = m.Status()
+	require.NoError(t, err)
+	assert.False(t, status.Behind())
+	assert.Equal(t, status.Latest, status.Version)
+
+	db, err := NewConnection(path)
+	require.NoError(t, err)
+	defer db.Close()
+	assert.NoError(t, CheckMigrations(context.Background(), db))
+}
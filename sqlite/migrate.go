@@ -0,0 +1,101 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+
+	"github.com/guilherme-santos/user/sqlstorage"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// migrations are embedded, so the binary doesn't depend on the SQL files.
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrator applies the embedded migrations to the database. There's no
+// advisory lock, each migration runs in a transaction which locks the
+// database for writing.
+type Migrator struct {
+	m *migrate.Migrate
+}
+
+// NewMigrator returns a Migrator of the database file at path. It has its own
+// connection because migrate closes the database when it's closed.
+func NewMigrator(path string) (*Migrator, error) {
+	src, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", DSN(path))
+	if err != nil {
+		return nil, err
+	}
+	driver, err := migratesqlite.WithInstance(db, &migratesqlite.Config{})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	m, err := migrate.NewWithInstance("iofs", src, "sqlite", driver)
+	if err != nil {
+		driver.Close()
+		return nil, err
+	}
+	return &Migrator{
+		m: m,
+	}, nil
+}
+
+// Up applies all migrations not applied yet.
+func (m *Migrator) Up(context.Context) error {
+	return ignoreNoChange(m.m.Up())
+}
+
+// Down rolls back the last steps migrations.
+func (m *Migrator) Down(_ context.Context, steps int) error {
+	return ignoreNoChange(m.m.Steps(-steps))
+}
+
+// Force sets the version of the schema without running any migration and
+// clears the dirty flag, e.g. after fixing a failed migration by hand.
+func (m *Migrator) Force(_ context.Context, version int) error {
+	return m.m.Force(version)
+}
+
+func (m *Migrator) Status() (*sqlstorage.MigrationStatus, error) {
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, err
+	}
+	latest, err := sqlstorage.LatestMigration(migrations)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlstorage.MigrationStatus{
+		Version: version,
+		Dirty:   dirty,
+		Latest:  latest,
+	}, nil
+}
+
+// Close closes the connection of the migrator.
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	if srcErr != nil {
+		return srcErr
+	}
+	return dbErr
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
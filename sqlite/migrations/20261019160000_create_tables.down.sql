@@ -0,0 +1,5 @@
+DROP TABLE user_role;
+DROP TABLE refresh_token;
+DROP TABLE api_key;
+DROP TABLE user_audit;
+DROP TABLE user;
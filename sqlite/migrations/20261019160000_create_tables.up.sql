@@ -0,0 +1,88 @@
+-- The schema is the same as the one of mysql after 20261019140000_add_tenant.
+-- Times are UTC texts in the format written by the driver, so they're sorted
+-- as text.
+
+CREATE TABLE user (
+  id CHAR(20) NOT NULL,
+  tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+  first_name VARCHAR(100) NOT NULL,
+  last_name VARCHAR(100) NOT NULL,
+  nickname VARCHAR(100) NOT NULL,
+  password VARCHAR(255) NOT NULL,
+  email VARCHAR(255) NOT NULL,
+  country CHAR(2) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+  updated_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+  removed_at DATETIME NULL,
+  PRIMARY KEY (id),
+  UNIQUE (tenant_id, email)
+);
+
+-- updated_at is set by a trigger, like ON UPDATE of mysql.
+CREATE TRIGGER user_updated_at AFTER UPDATE ON user
+  FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+  UPDATE user SET updated_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now') WHERE id = NEW.id;
+END;
+
+CREATE TABLE user_audit (
+  id CHAR(20) NOT NULL,
+  user_id CHAR(20) NOT NULL,
+  action VARCHAR(50) NOT NULL,
+  actor VARCHAR(255) NOT NULL,
+  request_id VARCHAR(255) NOT NULL DEFAULT '',
+  changes TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX user_audit_user_id_created_at ON user_audit (user_id, created_at);
+
+-- The audit log is append-only.
+CREATE TRIGGER user_audit_no_update BEFORE UPDATE ON user_audit
+BEGIN
+  SELECT RAISE(ABORT, 'user_audit is append-only');
+END;
+
+CREATE TRIGGER user_audit_no_delete BEFORE DELETE ON user_audit
+BEGIN
+  SELECT RAISE(ABORT, 'user_audit is append-only');
+END;
+
+CREATE TABLE api_key (
+  id CHAR(20) NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  key_hash CHAR(64) NOT NULL,
+  scopes TEXT NOT NULL,
+  tenant_id VARCHAR(64) NULL,
+  expires_at DATETIME NULL,
+  last_used_at DATETIME NULL,
+  created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+  revoked_at DATETIME NULL,
+  PRIMARY KEY (id),
+  UNIQUE (key_hash)
+);
+
+CREATE TABLE refresh_token (
+  id CHAR(20) NOT NULL,
+  family_id CHAR(20) NOT NULL,
+  user_id CHAR(20) NOT NULL,
+  tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+  token_hash CHAR(64) NOT NULL,
+  expires_at DATETIME NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+  used_at DATETIME NULL,
+  revoked_at DATETIME NULL,
+  PRIMARY KEY (id),
+  UNIQUE (token_hash)
+);
+
+CREATE INDEX refresh_token_family_id ON refresh_token (family_id);
+
+CREATE TABLE user_role (
+  user_id CHAR(20) NOT NULL,
+  role VARCHAR(32) NOT NULL,
+  granted_by VARCHAR(255) NOT NULL,
+  granted_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+  PRIMARY KEY (user_id, role)
+);
@@ -0,0 +1 @@
+DROP INDEX refresh_token_user_id;
@@ -0,0 +1 @@
+CREATE INDEX refresh_token_user_id ON refresh_token (user_id);
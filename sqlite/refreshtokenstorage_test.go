@@ -0,0 +1,54 @@
+package sqlite
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guilherme-santos/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshTokenStorageConsumeConcurrent(t *testing.T) {
+	ctx := context.Background()
+	s := NewRefreshTokenStorage(newDB(t))
+
+	now := time.Now()
+	require.NoError(t, s.Create(ctx, &user.RefreshToken{
+		FamilyID:  "family-1",
+		UserID:    "user-1",
+		TenantID:  user.DefaultTenant,
+		ExpiresAt: now.Add(time.Hour),
+		CreatedAt: now,
+	}, "hash"))
+
+	const n = 10
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		unused int
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tok, err := s.Consume(ctx, "hash")
+			if err != nil {
+				// writers waiting longer than busy_timeout are rejected
+				return
+			}
+			if tok.UsedAt == nil {
+				mu.Lock()
+				unused++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// only one request can use the token
+	assert.Equal(t, 1, unused)
+}
@@ -0,0 +1,27 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/storagetest"
+)
+
+func TestUserStorage(t *testing.T) {
+	db := newDB(t)
+	storagetest.Run(t, func(t *testing.T) user.Storage {
+		return NewUserStorage(db)
+	})
+}
+
+func TestAPIKeyStorage(t *testing.T) {
+	storagetest.RunAPIKeys(t, NewAPIKeyStorage(newDB(t)))
+}
+
+func TestRefreshTokenStorage(t *testing.T) {
+	storagetest.RunRefreshTokens(t, NewRefreshTokenStorage(newDB(t)))
+}
+
+func TestRoleStorage(t *testing.T) {
+	storagetest.RunRoles(t, NewRoleStorage(newDB(t)))
+}
@@ -0,0 +1,117 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/guilherme-santos/user"
+
+	"github.com/rs/xid"
+)
+
+// APIKeyStorage stores the API keys in the api_key table.
+type APIKeyStorage struct {
+	db      DB
+	dialect Dialect
+}
+
+// Make sure APIKeyStorage implements user.APIKeyStorage
+var _ user.APIKeyStorage = &APIKeyStorage{}
+
+func NewAPIKeyStorage(db DB, dialect Dialect) *APIKeyStorage {
+	return &APIKeyStorage{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+func (s APIKeyStorage) Create(ctx context.Context, k *user.APIKey, hash string) error {
+	ctx = WithCaller(ctx, "APIKeyStorage.Create")
+	id := xid.New().String()
+	scopes, err := json.Marshal(k.Scopes)
+	if err != nil {
+		return err
+	}
+	createdAt := time.Now().UTC().Truncate(time.Second)
+
+	query := `
+		INSERT INTO api_key
+			(id, name, key_hash, scopes, tenant_id, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = s.db.ExecContext(ctx, s.dialect.Rebind(query),
+		id,
+		k.Name,
+		hash,
+		string(scopes),
+		sql.NullString{String: k.TenantID, Valid: k.TenantID != ""},
+		k.ExpiresAt,
+		createdAt,
+	)
+	if err != nil {
+		return err
+	}
+	k.ID = id
+	k.CreatedAt = createdAt
+	return nil
+}
+
+func (s APIKeyStorage) GetByHash(ctx context.Context, hash string) (*user.APIKey, error) {
+	ctx = WithCaller(ctx, "APIKeyStorage.GetByHash")
+	query := `
+		SELECT id, name, scopes, tenant_id, expires_at, last_used_at, created_at, revoked_at
+		FROM api_key
+		WHERE key_hash = ?
+	`
+	var scopes []byte
+	var tenant sql.NullString
+	k := new(user.APIKey)
+	err := s.db.QueryRowContext(ctx, s.dialect.Rebind(query), hash).Scan(
+		&k.ID,
+		&k.Name,
+		&scopes,
+		&tenant,
+		&k.ExpiresAt,
+		&k.LastUsedAt,
+		&k.CreatedAt,
+		&k.RevokedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+	k.TenantID = tenant.String
+	err = json.Unmarshal(scopes, &k.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (s APIKeyStorage) Revoke(ctx context.Context, id string) error {
+	ctx = WithCaller(ctx, "APIKeyStorage.Revoke")
+	query := `UPDATE api_key SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`
+	res, err := s.db.ExecContext(ctx, s.dialect.Rebind(query), time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return user.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (s APIKeyStorage) UpdateLastUsed(ctx context.Context, id string, t time.Time) error {
+	ctx = WithCaller(ctx, "APIKeyStorage.UpdateLastUsed")
+	query := `UPDATE api_key SET last_used_at = ? WHERE id = ?`
+	_, err := s.db.ExecContext(ctx, s.dialect.Rebind(query), t.UTC(), id)
+	return err
+}
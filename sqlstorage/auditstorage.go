@@ -0,0 +1,120 @@
+package sqlstorage
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/guilherme-santos/user"
+
+	"github.com/rs/xid"
+)
+
+// AuditStorage stores the audit log in the user_audit table, which has
+// triggers to reject any UPDATE or DELETE.
+type AuditStorage struct {
+	db      DB
+	dialect Dialect
+}
+
+func NewAuditStorage(db DB, dialect Dialect) *AuditStorage {
+	return &AuditStorage{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+func (s AuditStorage) Append(ctx context.Context, e *user.AuditEntry) error {
+	ctx = WithCaller(ctx, "AuditStorage.Append")
+	return appendAudit(ctx, s.db, s.dialect, e)
+}
+
+// appendAudit inserts e using db, which can be the transaction of the change
+// being recorded.
+func appendAudit(ctx context.Context, db Queryer, dialect Dialect, e *user.AuditEntry) error {
+	id := xid.New().String()
+	changes, err := json.Marshal(e.Changes)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO user_audit
+			(id, user_id, action, actor, request_id, changes, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = db.ExecContext(ctx, dialect.Rebind(query),
+		id,
+		e.UserID,
+		e.Action,
+		e.Actor,
+		e.RequestID,
+		// []byte is sent as bytea by PostgreSQL, jsonb must be text
+		string(changes),
+		e.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+	e.ID = id
+	return nil
+}
+
+func (s AuditStorage) List(ctx context.Context, userID string, opts *user.AuditListOptions) (*user.AuditListResponse, error) {
+	ctx = WithCaller(ctx, "AuditStorage.List")
+	var total int64
+	err := s.db.
+		QueryRowContext(ctx, s.dialect.Rebind(`SELECT COUNT(*) FROM user_audit WHERE user_id = ?`), userID).
+		Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, user_id, action, actor, request_id, changes, created_at
+		FROM user_audit
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+	`
+	query += " LIMIT " + strconv.FormatInt(opts.PerPage, 10)
+	query += " OFFSET " + strconv.FormatInt(int64(opts.Page)*opts.PerPage, 10)
+
+	rows, err := s.db.QueryContext(ctx, s.dialect.Rebind(query), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]*user.AuditEntry, 0)
+
+	for rows.Next() {
+		var changes []byte
+		e := new(user.AuditEntry)
+		err := rows.Scan(
+			&e.ID,
+			&e.UserID,
+			&e.Action,
+			&e.Actor,
+			&e.RequestID,
+			&changes,
+			&e.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(changes, &e.Changes)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &user.AuditListResponse{
+		Total:   total,
+		PerPage: opts.PerPage,
+		Entries: entries,
+	}, nil
+}
@@ -0,0 +1,77 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Queryer runs queries, with or without a transaction.
+type Queryer interface {
+	ExecContext(_ context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(_ context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(_ context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// DB is implemented by the instrumented DB of the mysql package, *sql.DB is
+// adapted by NewDB.
+type DB interface {
+	Queryer
+	BeginTx(_ context.Context, opts *sql.TxOptions) (Tx, error)
+}
+
+// Tx is a transaction begun by DB.
+type Tx interface {
+	Queryer
+	PrepareContext(_ context.Context, query string) (Stmt, error)
+	Commit() error
+	Rollback() error
+}
+
+// Stmt is a statement prepared by Tx.
+type Stmt interface {
+	ExecContext(_ context.Context, args ...interface{}) (sql.Result, error)
+	Close() error
+}
+
+// NewDB adapts db to DB.
+func NewDB(db *sql.DB) DB {
+	return sqlDB{DB: db}
+}
+
+type sqlDB struct {
+	*sql.DB
+}
+
+func (db sqlDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return sqlTx{Tx: tx}, nil
+}
+
+type sqlTx struct {
+	*sql.Tx
+}
+
+func (tx sqlTx) PrepareContext(ctx context.Context, query string) (Stmt, error) {
+	stmt, err := tx.Tx.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+type callerKey struct{}
+
+// WithCaller returns a copy of ctx whose queries are made by caller, e.g.
+// APIKeyStorage.Create, so instrumented databases can record it.
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// Caller returns the caller stored by WithCaller, empty when there's none.
+func Caller(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
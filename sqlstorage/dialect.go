@@ -0,0 +1,58 @@
+// Package sqlstorage implements the storages which are the same in every SQL
+// database, the mysql, postgres and sqlite packages use them with their
+// Dialect. The current time is always passed as an argument, so queries don't
+// depend on the function of each database, e.g. NOW().
+package sqlstorage
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialect contains what differs between the SQL databases.
+type Dialect struct {
+	// Placeholder returns the placeholder of the nth arg of a query, starting
+	// at 1, e.g. ? or $1.
+	Placeholder func(n int) string
+	// IsDuplicateError reports whether err is a violation of the unique key,
+	// named as the database reports it.
+	IsDuplicateError func(err error, key string) bool
+	// AbortsTxOnError means a failed query aborts its transaction, so
+	// queries which may fail need a savepoint, e.g. in PostgreSQL.
+	AbortsTxOnError bool
+
+	// UserTable is the table of the users, user is reserved in PostgreSQL.
+	UserTable string
+	// UserEmailKey and RoleKey are the unique keys of the e-mail of the
+	// users and of the roles granted to a user, as IsDuplicateError names
+	// them.
+	UserEmailKey string
+	RoleKey      string
+}
+
+// QuestionPlaceholder is the placeholder of MySQL and SQLite.
+func QuestionPlaceholder(int) string {
+	return "?"
+}
+
+// DollarPlaceholder is the placeholder of PostgreSQL, e.g. $1.
+func DollarPlaceholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// Rebind replaces the ? placeholders of query by the ones of d.
+func (d Dialect) Rebind(query string) string {
+	var b strings.Builder
+	n := 0
+	for {
+		i := strings.IndexByte(query, '?')
+		if i < 0 {
+			b.WriteString(query)
+			return b.String()
+		}
+		n++
+		b.WriteString(query[:i])
+		b.WriteString(d.Placeholder(n))
+		query = query[i+1:]
+	}
+}
@@ -0,0 +1,28 @@
+package sqlstorage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guilherme-santos/user/sqlstorage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialectRebind(t *testing.T) {
+	query := `UPDATE api_key SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`
+
+	mysql := sqlstorage.Dialect{Placeholder: sqlstorage.QuestionPlaceholder}
+	assert.Equal(t, query, mysql.Rebind(query))
+
+	postgres := sqlstorage.Dialect{Placeholder: sqlstorage.DollarPlaceholder}
+	assert.Equal(t, `UPDATE api_key SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`, postgres.Rebind(query))
+}
+
+func TestCaller(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, sqlstorage.Caller(ctx))
+
+	ctx = sqlstorage.WithCaller(ctx, "APIKeyStorage.Create")
+	assert.Equal(t, "APIKeyStorage.Create", sqlstorage.Caller(ctx))
+}
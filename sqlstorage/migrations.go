@@ -0,0 +1,81 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+var ErrNoMigration = errors.New("no migration applied")
+
+// MigrationStatus is the version of the schema.
+type MigrationStatus struct {
+	// Version is the last migration applied, zero if none.
+	Version uint `json:"version"`
+	// Dirty means the last migration failed halfway, it must be fixed by
+	// hand and forced.
+	Dirty bool `json:"dirty"`
+	// Latest is the last migration embedded in the binary.
+	Latest uint `json:"latest"`
+}
+
+// Behind returns whether the schema misses migrations of the binary, or the
+// last one failed.
+func (s MigrationStatus) Behind() bool {
+	return s.Dirty || s.Version < s.Latest
+}
+
+// CheckMigrations returns an error when the schema is behind the migrations
+// of the migrations directory of fsys, or when a migration failed halfway.
+// Schemas ahead of it are accepted, e.g. while a newer version is rolled out.
+func CheckMigrations(ctx context.Context, db *sql.DB, fsys fs.FS) error {
+	var status MigrationStatus
+	err := db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&status.Version, &status.Dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNoMigration
+	}
+	if err != nil {
+		return err
+	}
+	status.Latest, err = LatestMigration(fsys)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case status.Dirty:
+		return fmt.Errorf("migration %d failed, schema is dirty", status.Version)
+	case status.Behind():
+		return fmt.Errorf("schema is at migration %d, expected %d", status.Version, status.Latest)
+	}
+	return nil
+}
+
+// LatestMigration returns the version of the last migration of the
+// migrations directory of fsys.
+func LatestMigration(fsys fs.FS) (uint, error) {
+	src, err := iofs.New(fsys, "migrations")
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
@@ -0,0 +1,39 @@
+package sqlstorage_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/guilherme-santos/user/sqlstorage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatestMigration(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/1_create.up.sql":   {},
+		"migrations/1_create.down.sql": {},
+		"migrations/3_alter.up.sql":    {},
+		"migrations/3_alter.down.sql":  {},
+		"migrations/2_index.up.sql":    {},
+		"migrations/2_index.down.sql":  {},
+	}
+	latest, err := sqlstorage.LatestMigration(fsys)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), latest)
+}
+
+func TestMigrationStatusBehind(t *testing.T) {
+	tests := []struct {
+		status sqlstorage.MigrationStatus
+		behind bool
+	}{
+		{status: sqlstorage.MigrationStatus{Version: 2, Latest: 2}},
+		{status: sqlstorage.MigrationStatus{Version: 3, Latest: 2}},
+		{status: sqlstorage.MigrationStatus{Version: 1, Latest: 2}, behind: true},
+		{status: sqlstorage.MigrationStatus{Version: 2, Latest: 2, Dirty: true}, behind: true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.behind, tt.status.Behind(), "%+v", tt.status)
+	}
+}
@@ -0,0 +1,114 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/guilherme-santos/user"
+
+	"github.com/rs/xid"
+)
+
+// RefreshTokenStorage stores the refresh tokens in the refresh_token table.
+type RefreshTokenStorage struct {
+	db      DB
+	dialect Dialect
+}
+
+// Make sure RefreshTokenStorage implements user.RefreshTokenStorage
+var _ user.RefreshTokenStorage = &RefreshTokenStorage{}
+
+func NewRefreshTokenStorage(db DB, dialect Dialect) *RefreshTokenStorage {
+	return &RefreshTokenStorage{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+func (s RefreshTokenStorage) Create(ctx context.Context, t *user.RefreshToken, hash string) error {
+	ctx = WithCaller(ctx, "RefreshTokenStorage.Create")
+	id := xid.New().String()
+
+	query := `
+		INSERT INTO refresh_token
+			(id, family_id, user_id, tenant_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := s.db.ExecContext(ctx, s.dialect.Rebind(query),
+		id,
+		t.FamilyID,
+		t.UserID,
+		t.TenantID,
+		hash,
+		t.ExpiresAt.UTC(),
+		t.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+	t.ID = id
+	return nil
+}
+
+// Consume marks the token as used only if it wasn't used yet, so two
+// concurrent requests can't both use it: the one which doesn't update it gets
+// it as used.
+func (s RefreshTokenStorage) Consume(ctx context.Context, hash string) (*user.RefreshToken, error) {
+	ctx = WithCaller(ctx, "RefreshTokenStorage.Consume")
+	query := `
+		SELECT id, family_id, user_id, tenant_id, expires_at, created_at, used_at, revoked_at
+		FROM refresh_token
+		WHERE token_hash = ?
+	`
+	t := new(user.RefreshToken)
+	err := s.db.QueryRowContext(ctx, s.dialect.Rebind(query), hash).Scan(
+		&t.ID,
+		&t.FamilyID,
+		&t.UserID,
+		&t.TenantID,
+		&t.ExpiresAt,
+		&t.CreatedAt,
+		&t.UsedAt,
+		&t.RevokedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrTokenNotFound
+		}
+		return nil, err
+	}
+	if t.UsedAt != nil {
+		return t, nil
+	}
+
+	now := time.Now().UTC()
+	query = `UPDATE refresh_token SET used_at = ? WHERE id = ? AND used_at IS NULL`
+	res, err := s.db.ExecContext(ctx, s.dialect.Rebind(query), now, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		// used by a concurrent request after it was read
+		t.UsedAt = &now
+	}
+	return t, nil
+}
+
+func (s RefreshTokenStorage) RevokeFamily(ctx context.Context, familyID string) error {
+	ctx = WithCaller(ctx, "RefreshTokenStorage.RevokeFamily")
+	query := `UPDATE refresh_token SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL`
+	_, err := s.db.ExecContext(ctx, s.dialect.Rebind(query), time.Now().UTC(), familyID)
+	return err
+}
+
+func (s RefreshTokenStorage) RevokeUser(ctx context.Context, userID string) error {
+	ctx = WithCaller(ctx, "RefreshTokenStorage.RevokeUser")
+	query := `UPDATE refresh_token SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`
+	_, err := s.db.ExecContext(ctx, s.dialect.Rebind(query), time.Now().UTC(), userID)
+	return err
+}
@@ -0,0 +1,89 @@
+package sqlstorage
+
+import (
+	"context"
+
+	"github.com/guilherme-santos/user"
+)
+
+// RoleStorage stores the roles granted to users in the user_role table.
+type RoleStorage struct {
+	db      DB
+	dialect Dialect
+}
+
+// Make sure RoleStorage implements user.RoleStorage
+var _ user.RoleStorage = &RoleStorage{}
+
+func NewRoleStorage(db DB, dialect Dialect) *RoleStorage {
+	return &RoleStorage{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+func (s RoleStorage) Roles(ctx context.Context, userID string) ([]*user.RoleAssignment, error) {
+	ctx = WithCaller(ctx, "RoleStorage.Roles")
+	query := `
+		SELECT user_id, role, granted_by, granted_at
+		FROM user_role
+		WHERE user_id = ?
+		ORDER BY role
+	`
+	rows, err := s.db.QueryContext(ctx, s.dialect.Rebind(query), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := make([]*user.RoleAssignment, 0)
+
+	for rows.Next() {
+		ra := new(user.RoleAssignment)
+		err := rows.Scan(&ra.UserID, &ra.Role, &ra.GrantedBy, &ra.GrantedAt)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, ra)
+	}
+	return roles, rows.Err()
+}
+
+func (s RoleStorage) Grant(ctx context.Context, ra *user.RoleAssignment) error {
+	ctx = WithCaller(ctx, "RoleStorage.Grant")
+	query := `
+		INSERT INTO user_role
+			(user_id, role, granted_by, granted_at)
+		VALUES (?, ?, ?, ?)
+	`
+	_, err := s.db.ExecContext(ctx, s.dialect.Rebind(query),
+		ra.UserID,
+		ra.Role,
+		ra.GrantedBy,
+		ra.GrantedAt.UTC(),
+	)
+	if err != nil {
+		if s.dialect.IsDuplicateError(err, s.dialect.RoleKey) {
+			return user.ErrRoleGranted
+		}
+		return err
+	}
+	return nil
+}
+
+func (s RoleStorage) Revoke(ctx context.Context, userID, role string) error {
+	ctx = WithCaller(ctx, "RoleStorage.Revoke")
+	query := `DELETE FROM user_role WHERE user_id = ? AND role = ?`
+	res, err := s.db.ExecContext(ctx, s.dialect.Rebind(query), userID, role)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return user.ErrRoleNotFound
+	}
+	return nil
+}
@@ -0,0 +1,585 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/metrics"
+
+	"github.com/rs/xid"
+	"go.opentelemetry.io/otel"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var tracer = otel.Tracer("github.com/guilherme-santos/user/sqlstorage")
+
+// UserStorage stores the users in the table of Dialect.UserTable.
+type UserStorage struct {
+	db      DB
+	dialect Dialect
+}
+
+func NewUserStorage(db DB, dialect Dialect) *UserStorage {
+	return &UserStorage{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+func (s UserStorage) Create(ctx context.Context, u *user.User) error {
+	return s.create(WithCaller(ctx, "UserStorage.Create"), u, nil)
+}
+
+// CreateAudited creates u and appends e to the audit log within the same
+// transaction, UserID of e is set to the new id.
+func (s UserStorage) CreateAudited(ctx context.Context, u *user.User, e *user.AuditEntry) error {
+	return s.create(WithCaller(ctx, "UserStorage.CreateAudited"), u, e)
+}
+
+// create creates u and appends e to the audit log, nothing is appended when
+// e is nil.
+func (s UserStorage) create(ctx context.Context, u *user.User, e *user.AuditEntry) error {
+	passwd, err := userPassword(ctx, u)
+	if err != nil {
+		return err
+	}
+	var id string
+	err = s.audited(ctx, e, func(db Queryer) error {
+		var err error
+		id, err = s.insert(ctx, queryExecer{db: db, query: s.insertQuery()}, u, passwd)
+		if err != nil {
+			return err
+		}
+		if e != nil {
+			e.UserID = id
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	u.ID = id
+	u.TenantID = user.Tenant(ctx)
+	return nil
+}
+
+// CreateBatch creates all users, and appends their entries to the audit log,
+// within the same transaction. Users are inserted one by one so each of them
+// has its own error, every insert has a savepoint when the transaction is
+// aborted on errors, see Dialect.AbortsTxOnError.
+//
+// Passwords are hashed before the transaction starts, so it isn't held open
+// while bcrypt runs, see hashPasswords.
+func (s UserStorage) CreateBatch(ctx context.Context, users []*user.User, entries []*user.AuditEntry, atomic bool) ([]error, error) {
+	ctx = WithCaller(ctx, "UserStorage.CreateBatch")
+	var failed bool
+	ids := make([]string, len(users))
+	passwds, errs := hashPasswords(ctx, users)
+
+	for _, err := range errs {
+		if err != nil {
+			failed = true
+		}
+	}
+	if failed && atomic {
+		return errs, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, s.insertQuery())
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	for i, u := range users {
+		if errs[i] != nil {
+			continue
+		}
+		err = s.savepoint(ctx, tx, "SAVEPOINT insert_user")
+		if err != nil {
+			return nil, err
+		}
+		ids[i], errs[i] = s.insert(ctx, stmt, u, passwds[i])
+		if errs[i] != nil {
+			var uerr *user.Error
+			if !errors.As(errs[i], &uerr) {
+				// not a problem with the user itself, abort everything.
+				return nil, errs[i]
+			}
+			failed = true
+			err = s.savepoint(ctx, tx, "ROLLBACK TO SAVEPOINT insert_user")
+		} else {
+			if entries != nil {
+				entries[i].UserID = ids[i]
+				err = s.appendAudit(ctx, tx, entries[i])
+				if err != nil {
+					return nil, err
+				}
+			}
+			err = s.savepoint(ctx, tx, "RELEASE SAVEPOINT insert_user")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if failed && atomic {
+		return errs, nil
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	for i, u := range users {
+		if errs[i] == nil {
+			u.ID = ids[i]
+			u.TenantID = user.Tenant(ctx)
+		}
+	}
+	return errs, nil
+}
+
+// savepoint runs query, which creates, rolls back or releases a savepoint,
+// only when the dialect needs them.
+func (s UserStorage) savepoint(ctx context.Context, tx Tx, query string) error {
+	if !s.dialect.AbortsTxOnError {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
+
+func (s UserStorage) insertQuery() string {
+	return s.dialect.Rebind(`
+		INSERT INTO ` + s.dialect.UserTable + `
+			(id, tenant_id, first_name, last_name, nickname, password, email, country)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+}
+
+// execer is implemented by Stmt, it's used to share the insert of a user
+// between Create and CreateBatch.
+type execer interface {
+	ExecContext(ctx context.Context, args ...interface{}) (sql.Result, error)
+}
+
+// queryExecer adapts Queryer to execer always running the same query.
+type queryExecer struct {
+	db    Queryer
+	query string
+}
+
+func (e queryExecer) ExecContext(ctx context.Context, args ...interface{}) (sql.Result, error) {
+	return e.db.ExecContext(ctx, e.query, args...)
+}
+
+// insert inserts u with the password hash passwd using db and returns its
+// new id.
+func (s UserStorage) insert(ctx context.Context, db execer, u *user.User, passwd string) (string, error) {
+	id := xid.New().String()
+	_, err := db.ExecContext(ctx,
+		id,
+		user.Tenant(ctx),
+		u.FirstName,
+		u.LastName,
+		u.Nickname,
+		passwd,
+		u.Email,
+		u.Country,
+	)
+	if err != nil {
+		if s.dialect.IsDuplicateError(err, s.dialect.UserEmailKey) {
+			return "", emailExistsError()
+		}
+		return "", err
+	}
+	return id, nil
+}
+
+// emailExistsError returns the error of an e-mail used by another user of
+// the tenant.
+func emailExistsError() error {
+	return &user.FieldError{
+		Err: user.Error{
+			Type:    user.InvalidArgument,
+			Code:    "email_already_exists",
+			Message: "Provided e-mail already exists",
+		},
+		Field: "email",
+	}
+}
+
+func (s UserStorage) Update(ctx context.Context, u *user.User) error {
+	return s.update(WithCaller(ctx, "UserStorage.Update"), u, nil)
+}
+
+// UpdateAudited updates u and appends e to the audit log within the same
+// transaction.
+func (s UserStorage) UpdateAudited(ctx context.Context, u *user.User, e *user.AuditEntry) error {
+	return s.update(WithCaller(ctx, "UserStorage.UpdateAudited"), u, e)
+}
+
+// update updates u and appends e to the audit log, nothing is appended when
+// e is nil.
+func (s UserStorage) update(ctx context.Context, u *user.User, e *user.AuditEntry) error {
+	query := `
+		UPDATE ` + s.dialect.UserTable + `
+		SET
+			first_name = ?,
+			last_name = ?,
+			nickname = ?,
+			email = ?,
+			country = ?
+	`
+	args := []interface{}{
+		u.FirstName,
+		u.LastName,
+		u.Nickname,
+		u.Email,
+		u.Country,
+	}
+	if u.Password != "" {
+		query += ", password = ?"
+		passwd, err := hashPassword(ctx, u.Password)
+		if err != nil {
+			return &user.FieldError{
+				Err: user.Error{
+					Type:    user.InvalidArgument,
+					Code:    "invalid_password",
+					Message: err.Error(),
+				},
+				Field: "password",
+			}
+		}
+		args = append(args, passwd)
+	}
+	query += " WHERE id = ? AND tenant_id = ?"
+	args = append(args, u.ID, user.Tenant(ctx))
+
+	u.TenantID = user.Tenant(ctx)
+	err := s.audited(ctx, e, func(db Queryer) error {
+		_, err := db.ExecContext(ctx, s.dialect.Rebind(query), args...)
+		return err
+	})
+	if err != nil {
+		if s.dialect.IsDuplicateError(err, s.dialect.UserEmailKey) {
+			return emailExistsError()
+		}
+	}
+	return err
+}
+
+func (s UserStorage) Delete(ctx context.Context, id string) error {
+	return s.delete(WithCaller(ctx, "UserStorage.Delete"), id, nil)
+}
+
+// DeleteAudited deletes the user with id and appends e to the audit log
+// within the same transaction.
+func (s UserStorage) DeleteAudited(ctx context.Context, id string, e *user.AuditEntry) error {
+	return s.delete(WithCaller(ctx, "UserStorage.DeleteAudited"), id, e)
+}
+
+// delete deletes the user with id and appends e to the audit log, nothing is
+// appended when e is nil.
+func (s UserStorage) delete(ctx context.Context, id string, e *user.AuditEntry) error {
+	query := `UPDATE ` + s.dialect.UserTable + ` SET removed_at = ? WHERE id = ? AND tenant_id = ? AND removed_at IS NULL`
+	return s.audited(ctx, e, func(db Queryer) error {
+		res, err := db.ExecContext(ctx, s.dialect.Rebind(query), time.Now().UTC(), id, user.Tenant(ctx))
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return user.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// audited runs fn and appends e to the audit log within the same transaction,
+// fn runs without a transaction when e is nil.
+func (s UserStorage) audited(ctx context.Context, e *user.AuditEntry, fn func(Queryer) error) error {
+	if e == nil {
+		return fn(s.db)
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = fn(tx)
+	if err != nil {
+		return err
+	}
+	err = s.appendAudit(ctx, tx, e)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s UserStorage) appendAudit(ctx context.Context, db Queryer, e *user.AuditEntry) error {
+	return appendAudit(ctx, db, s.dialect, e)
+}
+
+func (s UserStorage) Get(ctx context.Context, id string) (*user.User, error) {
+	ctx = WithCaller(ctx, "UserStorage.Get")
+	// users of other tenants are not found, like the ones that don't exist
+	query := s.selectQuery() + " WHERE id = ? AND tenant_id = ?"
+
+	row := s.db.QueryRowContext(ctx, s.dialect.Rebind(query), id, user.Tenant(ctx))
+	u, err := scanUser(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrNotFound
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+// GetCredentials returns the id and the password hash of the active user
+// with email.
+func (s UserStorage) GetCredentials(ctx context.Context, email string) (string, string, error) {
+	ctx = WithCaller(ctx, "UserStorage.GetCredentials")
+	query := `SELECT id, password FROM ` + s.dialect.UserTable + ` WHERE tenant_id = ? AND email = ? AND removed_at IS NULL`
+
+	var id, hash string
+	err := s.db.QueryRowContext(ctx, s.dialect.Rebind(query), user.Tenant(ctx), email).Scan(&id, &hash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", "", user.ErrNotFound
+		}
+		return "", "", err
+	}
+	return id, hash, nil
+}
+
+func (s UserStorage) selectQuery() string {
+	return `
+		SELECT id, tenant_id, first_name, last_name, nickname, email, country, created_at, updated_at, removed_at
+		FROM ` + s.dialect.UserTable
+}
+
+func (s UserStorage) List(ctx context.Context, opts *user.ListOptions) (*user.ListResponse, error) {
+	ctx = WithCaller(ctx, "UserStorage.List")
+	filter, args, err := listFilter(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	query := s.selectQuery() + filter
+	query += " LIMIT " + strconv.FormatInt(opts.PerPage, 10)
+	if opts.Cursor == "" {
+		query += " OFFSET " + strconv.FormatInt(int64(opts.Page)*opts.PerPage, 10)
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*user.User, 0)
+
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	resp := &user.ListResponse{
+		Total:   int64(len(users)),
+		PerPage: opts.PerPage,
+		Users:   users,
+	}
+	// a full page may have a next one
+	if opts.SortedByID() && len(users) > 0 && int64(len(users)) == opts.PerPage {
+		resp.NextCursor = users[len(users)-1].ID
+	}
+
+	// TODO: get total of users using COUNT(*) from the SELECT
+
+	return resp, nil
+}
+
+// Export streams all users matching opts calling fn for each one of them,
+// pagination is ignored. It runs in a read-only transaction so all users come
+// from the same snapshot.
+func (s UserStorage) Export(ctx context.Context, opts *user.ListOptions, fn func(*user.User) error) error {
+	ctx = WithCaller(ctx, "UserStorage.Export")
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	filter, args, err := listFilter(ctx, opts)
+	if err != nil {
+		return err
+	}
+	rows, err := tx.QueryContext(ctx, s.dialect.Rebind(s.selectQuery()+filter), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return err
+		}
+		err = fn(u)
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// sortColumns are the columns of the fields users can be sorted by, sort
+// fields are never added to queries as they're.
+var sortColumns = map[string]string{
+	"id":         "id",
+	"first_name": "first_name",
+	"last_name":  "last_name",
+	"nickname":   "nickname",
+	"email":      "email",
+	"country":    "country",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// listFilter returns the WHERE and ORDER BY clauses with its args for opts,
+// only users of the tenant of ctx are listed.
+func listFilter(ctx context.Context, opts *user.ListOptions) (string, []interface{}, error) {
+	where := []string{"tenant_id = ?", "removed_at IS NULL"}
+	args := []interface{}{user.Tenant(ctx)}
+
+	if opts.Country != "" {
+		where = append(where, "country = ?")
+		args = append(args, opts.Country)
+	}
+	if opts.Cursor != "" {
+		where = append(where, "id > ?")
+		args = append(args, opts.Cursor)
+	}
+	query := " WHERE " + strings.Join(where, " AND ")
+	field, desc := opts.SortField()
+	column, ok := sortColumns[field]
+	if !ok {
+		return "", nil, user.ErrInvalidSort
+	}
+	query += " ORDER BY " + column
+	if desc {
+		query += " DESC"
+	} else {
+		query += " ASC"
+	}
+	return query, args, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row rowScanner) (*user.User, error) {
+	u := new(user.User)
+	err := row.Scan(
+		&u.ID,
+		&u.TenantID,
+		&u.FirstName,
+		&u.LastName,
+		&u.Nickname,
+		&u.Email,
+		&u.Country,
+		&u.CreatedAt,
+		&u.UpdatedAt,
+		&u.RemovedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// hashPasswords returns the password hash to be stored for each user, or
+// the error hashing it. Passwords are hashed by up to GOMAXPROCS workers, as
+// each one takes about a second of CPU.
+func hashPasswords(ctx context.Context, users []*user.User) ([]string, []error) {
+	passwds := make([]string, len(users))
+	errs := make([]error, len(users))
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(users)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				passwds[i], errs[i] = userPassword(ctx, users[i])
+			}
+		}()
+	}
+	for i := range users {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return passwds, errs
+}
+
+// userPassword returns the password hash to be stored for u. If u has
+// PasswordHash it's stored as it's, otherwise Password is hashed.
+func userPassword(ctx context.Context, u *user.User) (string, error) {
+	if u.PasswordHash != "" {
+		return u.PasswordHash, nil
+	}
+	passwd, err := hashPassword(ctx, u.Password)
+	if err != nil {
+		return "", &user.FieldError{
+			Err: user.Error{
+				Type:    user.InvalidArgument,
+				Code:    "invalid_password",
+				Message: err.Error(),
+			},
+			Field: "password",
+		}
+	}
+	return passwd, nil
+}
+
+// hashPassword returns a hashed version from the password
+func hashPassword(ctx context.Context, p string) (string, error) {
+	_, span := tracer.Start(ctx, "bcrypt.GenerateFromPassword")
+	defer span.End()
+
+	t1 := time.Now()
+	bytes, err := bcrypt.GenerateFromPassword([]byte(p), 14)
+	metrics.PasswordHashDuration.Observe(time.Since(t1).Seconds())
+	return string(bytes), err
+}
@@ -0,0 +1,40 @@
+package sqlstorage
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashPasswords(t *testing.T) {
+	const hash = "$2a$04$3ZGj2hsHN8aZvJ5kaRuSWuKa6RX2P6vJQ9IBHTaTTEu/b3iP6Zm.a"
+
+	users := make([]*user.User, 20)
+	for i := range users {
+		users[i] = &user.User{PasswordHash: hash}
+	}
+	// bcrypt rejects passwords longer than 72 bytes without hashing them
+	users[7] = &user.User{Password: strings.Repeat("a", 73)}
+
+	passwds, errs := hashPasswords(context.Background(), users)
+	for i := range users {
+		if i == 7 {
+			assert.Empty(t, passwds[i])
+			var ferr *user.FieldError
+			if assert.ErrorAs(t, errs[i], &ferr) {
+				assert.Equal(t, "invalid_password", ferr.Code)
+			}
+			continue
+		}
+		assert.NoError(t, errs[i])
+		assert.Equal(t, hash, passwds[i])
+	}
+
+	passwds, errs = hashPasswords(context.Background(), nil)
+	assert.Empty(t, passwds)
+	assert.Empty(t, errs)
+}
@@ -0,0 +1,97 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guilherme-santos/user"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunAPIKeys runs the tests of user.APIKeyStorage against s, its schema must
+// be migrated.
+func RunAPIKeys(t *testing.T, s user.APIKeyStorage) {
+	ctx := context.Background()
+	hash := xid.New().String()
+
+	k := &user.APIKey{Name: "partner", Scopes: []string{"users:read"}, TenantID: newTenant()}
+	require.NoError(t, s.Create(ctx, k, hash))
+	assert.NotEmpty(t, k.ID)
+
+	got, err := s.GetByHash(ctx, hash)
+	require.NoError(t, err)
+	assert.Equal(t, k.ID, got.ID)
+	assert.Equal(t, []string{"users:read"}, got.Scopes)
+	assert.Equal(t, k.TenantID, got.TenantID)
+	assert.Nil(t, got.RevokedAt)
+
+	lastUsed := time.Now().Truncate(time.Second)
+	require.NoError(t, s.UpdateLastUsed(ctx, k.ID, lastUsed))
+	require.NoError(t, s.Revoke(ctx, k.ID))
+	got, err = s.GetByHash(ctx, hash)
+	require.NoError(t, err)
+	assert.NotNil(t, got.RevokedAt)
+	if assert.NotNil(t, got.LastUsedAt) {
+		assert.True(t, lastUsed.Equal(*got.LastUsedAt), "%v != %v", lastUsed, *got.LastUsedAt)
+	}
+
+	// revoking twice or unknown keys
+	assert.Equal(t, user.ErrAPIKeyNotFound, s.Revoke(ctx, k.ID))
+	_, err = s.GetByHash(ctx, xid.New().String())
+	assert.Equal(t, user.ErrAPIKeyNotFound, err)
+}
+
+// RunRefreshTokens runs the tests of user.RefreshTokenStorage against s, its
+// schema must be migrated.
+func RunRefreshTokens(t *testing.T, s user.RefreshTokenStorage) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	newToken := func(family, userID string) string {
+		hash := xid.New().String()
+		require.NoError(t, s.Create(ctx, &user.RefreshToken{
+			FamilyID:  family,
+			UserID:    userID,
+			TenantID:  user.DefaultTenant,
+			ExpiresAt: now.Add(time.Hour),
+			CreatedAt: now,
+		}, hash))
+		return hash
+	}
+
+	// tokens can be consumed only once
+	family, userID := xid.New().String(), xid.New().String()
+	hash := newToken(family, userID)
+	got, err := s.Consume(ctx, hash)
+	require.NoError(t, err)
+	assert.Equal(t, family, got.FamilyID)
+	assert.Equal(t, userID, got.UserID)
+	assert.Nil(t, got.UsedAt)
+	got, err = s.Consume(ctx, hash)
+	require.NoError(t, err)
+	assert.NotNil(t, got.UsedAt)
+
+	_, err = s.Consume(ctx, xid.New().String())
+	assert.Equal(t, user.ErrTokenNotFound, err)
+
+	// revoking the family
+	hash = newToken(family, userID)
+	require.NoError(t, s.RevokeFamily(ctx, family))
+	got, err = s.Consume(ctx, hash)
+	require.NoError(t, err)
+	assert.NotNil(t, got.RevokedAt)
+
+	// revoking all tokens of the user
+	hash = newToken(xid.New().String(), userID)
+	other := newToken(xid.New().String(), xid.New().String())
+	require.NoError(t, s.RevokeUser(ctx, userID))
+	got, err = s.Consume(ctx, hash)
+	require.NoError(t, err)
+	assert.NotNil(t, got.RevokedAt)
+	got, err = s.Consume(ctx, other)
+	require.NoError(t, err)
+	assert.Nil(t, got.RevokedAt)
+}
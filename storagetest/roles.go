@@ -0,0 +1,46 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guilherme-santos/user"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunRoles runs the tests of user.RoleStorage against s, its schema must be
+// migrated.
+func RunRoles(t *testing.T, s user.RoleStorage) {
+	ctx := context.Background()
+	userID := xid.New().String()
+	grant := func(role string) *user.RoleAssignment {
+		return &user.RoleAssignment{
+			UserID:    userID,
+			Role:      role,
+			GrantedBy: "cli",
+			GrantedAt: time.Now().Truncate(time.Second),
+		}
+	}
+
+	require.NoError(t, s.Grant(ctx, grant("viewer")))
+	require.NoError(t, s.Grant(ctx, grant("admin")))
+	assert.Equal(t, user.ErrRoleGranted, s.Grant(ctx, grant("admin")))
+
+	roles, err := s.Roles(ctx, userID)
+	require.NoError(t, err)
+	if assert.Len(t, roles, 2) {
+		assert.Equal(t, "admin", roles[0].Role)
+		assert.Equal(t, "viewer", roles[1].Role)
+		assert.Equal(t, "cli", roles[0].GrantedBy)
+	}
+
+	require.NoError(t, s.Revoke(ctx, userID, "admin"))
+	assert.Equal(t, user.ErrRoleNotFound, s.Revoke(ctx, userID, "admin"))
+	roles, err = s.Roles(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, roles, 1)
+}
@@ -0,0 +1,523 @@
+// Package storagetest is the conformance suite of user.Storage, every backend
+// runs it so all of them behave the same, e.g.:
+//
+//	func TestUserStorage(t *testing.T) {
+//		storagetest.Run(t, func(t *testing.T) user.Storage {
+//			return NewUserStorage(db)
+//		})
+//	}
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// passwordHash is stored instead of hashing a password, which is slow.
+const passwordHash = "$2a$04$3ZGj2hsHN8aZvJ5kaRuSWuKa6RX2P6vJQ9IBHTaTTEu/b3iP6Zm.a"
+
+// Factory returns the storage tested, its schema must be migrated. Each test
+// uses its own tenant, so the same database can be shared by all of them.
+type Factory func(t *testing.T) user.Storage
+
+// Run runs all tests of the suite against the storages returned by
+// newStorage.
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		test func(*testing.T, context.Context, user.Storage)
+	}{
+		{name: "CreateGet", test: testCreateGet},
+		{name: "CreateDuplicateEmail", test: testCreateDuplicateEmail},
+		{name: "UpdateDuplicateEmail", test: testUpdateDuplicateEmail},
+		{name: "UpdateKeepsPassword", test: testUpdateKeepsPassword},
+		{name: "GetOtherTenant", test: testGetOtherTenant},
+		{name: "Delete", test: testDelete},
+		{name: "ListCountry", test: testListCountry},
+		{name: "ListSort", test: testListSort},
+		{name: "ListPaging", test: testListPaging},
+		{name: "ListCursor", test: testListCursor},
+		{name: "Export", test: testExport},
+		{name: "Audited", test: testAudited},
+		{name: "AuditedRollback", test: testAuditedRollback},
+		{name: "CreateBatch", test: testCreateBatch},
+		{name: "ConcurrentCreate", test: testConcurrentCreate},
+		{name: "ConcurrentDuplicateEmail", test: testConcurrentDuplicateEmail},
+		{name: "ContextCanceled", test: testContextCanceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := user.SetTenant(context.Background(), newTenant())
+			tt.test(t, ctx, newStorage(t))
+		})
+	}
+}
+
+func newTenant() string {
+	return "test-" + xid.New().String()
+}
+
+// newUser returns a valid user, its email is unique.
+func newUser(firstName, country string) *user.User {
+	return &user.User{
+		FirstName:    firstName,
+		LastName:     "Santos",
+		Nickname:     "xguiga",
+		PasswordHash: passwordHash,
+		Email:        xid.New().String() + "@example.com",
+		Country:      country,
+	}
+}
+
+// create creates users failing the test on errors.
+func create(t *testing.T, ctx context.Context, s user.Storage, users ...*user.User) {
+	t.Helper()
+	for _, u := range users {
+		require.NoError(t, s.Create(ctx, u))
+	}
+}
+
+// assertErrorCode asserts err is a user.Error with code.
+func assertErrorCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var uerr *user.Error
+	if assert.True(t, errors.As(err, &uerr), "expected user.Error, got %v", err) {
+		assert.Equal(t, code, uerr.Code)
+	}
+}
+
+func testCreateGet(t *testing.T, ctx context.Context, s user.Storage) {
+	u := newUser("Guilherme", "DE")
+	create(t, ctx, s, u)
+	assert.NotEmpty(t, u.ID)
+	assert.Equal(t, user.Tenant(ctx), u.TenantID)
+
+	got, err := s.Get(ctx, u.ID)
+	require.NoError(t, err)
+	assert.Equal(t, u.ID, got.ID)
+	assert.Equal(t, u.TenantID, got.TenantID)
+	assert.Equal(t, u.FirstName, got.FirstName)
+	assert.Equal(t, u.LastName, got.LastName)
+	assert.Equal(t, u.Nickname, got.Nickname)
+	assert.Equal(t, u.Email, got.Email)
+	assert.Equal(t, u.Country, got.Country)
+	assert.False(t, got.CreatedAt.IsZero())
+	assert.NotNil(t, got.UpdatedAt)
+	assert.Nil(t, got.RemovedAt)
+	// the password is never returned
+	assert.Empty(t, got.Password)
+	assert.Empty(t, got.PasswordHash)
+
+	_, err = s.Get(ctx, xid.New().String())
+	assert.Equal(t, user.ErrNotFound, err)
+}
+
+func testCreateDuplicateEmail(t *testing.T, ctx context.Context, s user.Storage) {
+	u := newUser("Guilherme", "DE")
+	create(t, ctx, s, u)
+
+	dup := newUser("Other", "BR")
+	dup.Email = u.Email
+	err := s.Create(ctx, dup)
+	assertErrorCode(t, err, "email_already_exists")
+	var ferr *user.FieldError
+	if assert.True(t, errors.As(err, &ferr)) {
+		assert.Equal(t, "email", ferr.Field)
+	}
+
+	// emails are unique per tenant
+	other := user.SetTenant(ctx, newTenant())
+	dup = newUser("Other", "BR")
+	dup.Email = u.Email
+	assert.NoError(t, s.Create(other, dup))
+}
+
+func testUpdateDuplicateEmail(t *testing.T, ctx context.Context, s user.Storage) {
+	u1 := newUser("Guilherme", "DE")
+	u2 := newUser("Other", "BR")
+	create(t, ctx, s, u1, u2)
+
+	u2.Email = u1.Email
+	assertErrorCode(t, s.Update(ctx, u2), "email_already_exists")
+
+	u2.Email = "updated-" + u2.ID + "@example.com"
+	u2.Country = "PT"
+	require.NoError(t, s.Update(ctx, u2))
+
+	got, err := s.Get(ctx, u2.ID)
+	require.NoError(t, err)
+	assert.Equal(t, u2.Email, got.Email)
+	assert.Equal(t, "PT", got.Country)
+}
+
+func testUpdateKeepsPassword(t *testing.T, ctx context.Context, s user.Storage) {
+	cs, ok := s.(user.CredentialStorage)
+	if !ok {
+		t.Skip("storage doesn't implement user.CredentialStorage")
+	}
+
+	u := newUser("Guilherme", "DE")
+	create(t, ctx, s, u)
+
+	// users are updated without password when it isn't changed
+	u.FirstName = "Updated"
+	require.NoError(t, s.Update(ctx, u))
+
+	id, hash, err := cs.GetCredentials(ctx, u.Email)
+	require.NoError(t, err)
+	assert.Equal(t, u.ID, id)
+	assert.Equal(t, passwordHash, hash)
+
+	got, err := s.Get(ctx, u.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", got.FirstName)
+}
+
+func testGetOtherTenant(t *testing.T, ctx context.Context, s user.Storage) {
+	u := newUser("Guilherme", "DE")
+	create(t, ctx, s, u)
+
+	// users of other tenants are not found, like the ones that don't exist
+	other := user.SetTenant(ctx, newTenant())
+	_, err := s.Get(other, u.ID)
+	assert.Equal(t, user.ErrNotFound, err)
+	assert.Equal(t, user.ErrNotFound, s.Delete(other, u.ID))
+}
+
+func testDelete(t *testing.T, ctx context.Context, s user.Storage) {
+	u := newUser("Guilherme", "DE")
+	create(t, ctx, s, u)
+
+	require.NoError(t, s.Delete(ctx, u.ID))
+	assert.Equal(t, user.ErrNotFound, s.Delete(ctx, u.ID))
+
+	// users are soft deleted, they're still found by id
+	got, err := s.Get(ctx, u.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, got.RemovedAt)
+
+	resp, err := s.List(ctx, user.NewListOptions())
+	require.NoError(t, err)
+	assert.Empty(t, resp.Users)
+
+	if cs, ok := s.(user.CredentialStorage); ok {
+		// deleted users can't authenticate
+		_, _, err = cs.GetCredentials(ctx, u.Email)
+		assert.Equal(t, user.ErrNotFound, err)
+	}
+}
+
+func testListCountry(t *testing.T, ctx context.Context, s user.Storage) {
+	de := newUser("Guilherme", "DE")
+	create(t, ctx, s, de, newUser("Other", "BR"))
+
+	opts := user.NewListOptions()
+	opts.Country = "DE"
+	resp, err := s.List(ctx, opts)
+	require.NoError(t, err)
+	require.Len(t, resp.Users, 1)
+	assert.Equal(t, de.ID, resp.Users[0].ID)
+}
+
+func testListSort(t *testing.T, ctx context.Context, s user.Storage) {
+	create(t, ctx, s, newUser("b", "DE"), newUser("c", "DE"), newUser("a", "DE"))
+
+	tests := []struct {
+		sort     string
+		expected []string
+	}{
+		{sort: "first_name", expected: []string{"a", "b", "c"}},
+		{sort: "-first_name", expected: []string{"c", "b", "a"}},
+	}
+	for _, tt := range tests {
+		opts := user.NewListOptions()
+		opts.Sort = tt.sort
+		resp, err := s.List(ctx, opts)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, firstNames(resp.Users), tt.sort)
+	}
+
+	// sort fields never reach the query as they're
+	opts := user.NewListOptions()
+	opts.Sort = "(SELECT password)"
+	_, err := s.List(ctx, opts)
+	assert.Equal(t, user.ErrInvalidSort, err)
+}
+
+func testListPaging(t *testing.T, ctx context.Context, s user.Storage) {
+	create(t, ctx, s, newUser("a", "DE"), newUser("b", "DE"), newUser("c", "DE"))
+
+	opts := user.NewListOptions()
+	opts.Sort = "first_name"
+	opts.PerPage = 2
+
+	var pages [][]string
+	for opts.Page = 0; opts.Page < 3; opts.Page++ {
+		resp, err := s.List(ctx, opts)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resp.PerPage)
+		pages = append(pages, firstNames(resp.Users))
+	}
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}, {}}, pages)
+}
+
+func testListCursor(t *testing.T, ctx context.Context, s user.Storage) {
+	var ids []string
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		u := newUser(name, "DE")
+		create(t, ctx, s, u)
+		ids = append(ids, u.ID)
+	}
+	// deleted users are skipped
+	require.NoError(t, s.Delete(ctx, ids[2]))
+	ids = append(ids[:2], ids[3:]...)
+
+	opts := user.NewListOptions()
+	opts.PerPage = 2
+
+	// users are sorted by id by default, the cursor is the last id of a
+	// full page
+	var pages [][]string
+	for i := 0; i < len(ids); i++ {
+		resp, err := s.List(ctx, opts)
+		require.NoError(t, err)
+		pages = append(pages, userIDs(resp.Users))
+		if resp.NextCursor == "" {
+			break
+		}
+		assert.Equal(t, resp.Users[len(resp.Users)-1].ID, resp.NextCursor)
+		opts.Cursor = resp.NextCursor
+	}
+	assert.Equal(t, [][]string{ids[:2], ids[2:], {}}, pages)
+}
+
+func testExport(t *testing.T, ctx context.Context, s user.Storage) {
+	es, ok := s.(user.ExportStorage)
+	if !ok {
+		t.Skip("storage doesn't implement user.ExportStorage")
+	}
+	create(t, ctx, s, newUser("a", "DE"), newUser("b", "BR"), newUser("c", "DE"))
+
+	// pagination is ignored
+	opts := user.NewListOptions()
+	opts.Country = "DE"
+	opts.Sort = "first_name"
+	opts.PerPage = 1
+
+	var exported []*user.User
+	err := es.Export(ctx, opts, func(u *user.User) error {
+		exported = append(exported, u)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, firstNames(exported))
+}
+
+// concurrency is the number of goroutines writing at the same time.
+const concurrency = 10
+
+func testAudited(t *testing.T, ctx context.Context, s user.Storage) {
+	as, ok := s.(user.AuditedStorage)
+	if !ok {
+		t.Skip("storage doesn't implement user.AuditedStorage")
+	}
+	u := newUser("Guilherme", "DE")
+	e := user.NewAuditEntry(ctx, user.AuditCreated, nil, u)
+	require.NoError(t, as.CreateAudited(ctx, u, e))
+	assert.NotEmpty(t, u.ID)
+	assert.Equal(t, u.ID, e.UserID)
+	assert.NotEmpty(t, e.ID)
+
+	before := *u
+	u.FirstName = "Changed"
+	e = user.NewAuditEntry(ctx, user.AuditUpdated, &before, u)
+	require.NoError(t, as.UpdateAudited(ctx, u, e))
+	assert.NotEmpty(t, e.ID)
+
+	e = user.NewAuditEntry(ctx, user.AuditDeleted, u, nil)
+	require.NoError(t, as.DeleteAudited(ctx, u.ID, e))
+	assert.NotEmpty(t, e.ID)
+
+	err := as.DeleteAudited(ctx, u.ID, user.NewAuditEntry(ctx, user.AuditDeleted, u, nil))
+	assert.Equal(t, user.ErrNotFound, err)
+}
+
+// testAuditedRollback makes sure changes are rolled back when their entry
+// can't be appended.
+func testAuditedRollback(t *testing.T, ctx context.Context, s user.Storage) {
+	as, ok := s.(user.AuditedStorage)
+	if !ok {
+		t.Skip("storage doesn't implement user.AuditedStorage")
+	}
+	// changes can't be encoded as JSON
+	invalid := func(action string) *user.AuditEntry {
+		e := user.NewAuditEntry(ctx, action, nil, nil)
+		e.Changes["invalid"] = user.AuditChange{After: func() {}}
+		return e
+	}
+
+	u := newUser("Guilherme", "DE")
+	assert.Error(t, as.CreateAudited(ctx, u, invalid(user.AuditCreated)))
+	list, err := s.List(ctx, user.NewListOptions())
+	require.NoError(t, err)
+	assert.Empty(t, list.Users)
+
+	create(t, ctx, s, u)
+	changed := *u
+	changed.FirstName = "Changed"
+	assert.Error(t, as.UpdateAudited(ctx, &changed, invalid(user.AuditUpdated)))
+	assert.Error(t, as.DeleteAudited(ctx, u.ID, invalid(user.AuditDeleted)))
+
+	got, err := s.Get(ctx, u.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Guilherme", got.FirstName)
+	assert.Nil(t, got.RemovedAt)
+}
+
+// testCreateBatch makes sure a duplicate e-mail only fails its own user,
+// unless the batch is atomic.
+func testCreateBatch(t *testing.T, ctx context.Context, s user.Storage) {
+	bs, ok := s.(user.BatchStorage)
+	if !ok {
+		t.Skip("storage doesn't implement user.BatchStorage")
+	}
+	existing := newUser("Existing", "DE")
+	create(t, ctx, s, existing)
+
+	batch := func() ([]*user.User, []*user.AuditEntry) {
+		dup := newUser("Duplicate", "DE")
+		dup.Email = existing.Email
+		users := []*user.User{newUser("a", "DE"), dup, newUser("c", "DE")}
+		entries := make([]*user.AuditEntry, len(users))
+		for i, u := range users {
+			entries[i] = user.NewAuditEntry(ctx, user.AuditCreated, nil, u)
+		}
+		return users, entries
+	}
+
+	users, entries := batch()
+	errs, err := bs.CreateBatch(ctx, users, entries, true)
+	require.NoError(t, err)
+	assertErrorCode(t, errs[1], "email_already_exists")
+	list, err := s.List(ctx, user.NewListOptions())
+	require.NoError(t, err)
+	assert.Len(t, list.Users, 1)
+
+	users, entries = batch()
+	errs, err = bs.CreateBatch(ctx, users, entries, false)
+	require.NoError(t, err)
+	assert.NoError(t, errs[0])
+	assertErrorCode(t, errs[1], "email_already_exists")
+	assert.NoError(t, errs[2])
+	assert.Equal(t, users[2].ID, entries[2].UserID)
+
+	list, err = s.List(ctx, user.NewListOptions())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{existing.ID, users[0].ID, users[2].ID}, userIDs(list.Users))
+}
+
+func testConcurrentCreate(t *testing.T, ctx context.Context, s user.Storage) {
+	users := make([]*user.User, concurrency)
+	errs := make([]error, concurrency)
+
+	var wg sync.WaitGroup
+	for i := range users {
+		users[i] = newUser("Guilherme", "DE")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.Create(ctx, users[i])
+		}()
+	}
+	wg.Wait()
+
+	ids := make(map[string]bool)
+	for i, u := range users {
+		require.NoError(t, errs[i])
+		ids[u.ID] = true
+	}
+	assert.Len(t, ids, concurrency, "ids must be unique")
+
+	opts := user.NewListOptions()
+	opts.PerPage = 2 * concurrency
+	resp, err := s.List(ctx, opts)
+	require.NoError(t, err)
+	assert.Len(t, resp.Users, concurrency)
+}
+
+func testConcurrentDuplicateEmail(t *testing.T, ctx context.Context, s user.Storage) {
+	email := xid.New().String() + "@example.com"
+	errs := make([]error, concurrency)
+
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u := newUser("Guilherme", "DE")
+			u.Email = email
+			errs[i] = s.Create(ctx, u)
+		}()
+	}
+	wg.Wait()
+
+	// only one of them is created, the others fail as any duplicate
+	var created int
+	for _, err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assertErrorCode(t, err, "email_already_exists")
+	}
+	assert.Equal(t, 1, created)
+}
+
+func testContextCanceled(t *testing.T, ctx context.Context, s user.Storage) {
+	u := newUser("Guilherme", "DE")
+	create(t, ctx, s, u)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	err := s.Create(canceled, newUser("Other", "DE"))
+	assert.ErrorIs(t, err, context.Canceled)
+	u.FirstName = "Updated"
+	assert.ErrorIs(t, s.Update(canceled, u), context.Canceled)
+	assert.ErrorIs(t, s.Delete(canceled, u.ID), context.Canceled)
+	_, err = s.Get(canceled, u.ID)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = s.List(canceled, user.NewListOptions())
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// nothing was changed
+	resp, err := s.List(ctx, user.NewListOptions())
+	require.NoError(t, err)
+	if assert.Len(t, resp.Users, 1) {
+		assert.Equal(t, "Guilherme", resp.Users[0].FirstName)
+	}
+}
+
+func userIDs(users []*user.User) []string {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func firstNames(users []*user.User) []string {
+	names := make([]string, 0, len(users))
+	for _, u := range users {
+		names = append(names, u.FirstName)
+	}
+	return names
+}
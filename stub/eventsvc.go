@@ -32,12 +32,50 @@ func (s EventService) UserDeleted(ctx context.Context, u *user.User) error {
 	return nil
 }
 
+func (s EventService) RoleGranted(ctx context.Context, ra *user.RoleAssignment) error {
+	s.logRole(ctx, "role.granted", ra)
+	// TODO: publish event
+	return nil
+}
+
+func (s EventService) RoleRevoked(ctx context.Context, ra *user.RoleAssignment) error {
+	s.logRole(ctx, "role.revoked", ra)
+	// TODO: publish event
+	return nil
+}
+
+// Ping checks the connection with the message broker, it's used by the
+// health checks.
+func (s EventService) Ping(ctx context.Context) error {
+	// TODO: check the broker
+	return nil
+}
+
+// Flush waits for the events still being published, it's called on shutdown.
+func (s EventService) Flush(ctx context.Context) error {
+	// TODO: flush publisher
+	return nil
+}
+
+func (s EventService) logRole(ctx context.Context, event string, ra *user.RoleAssignment) {
+	log := user.Logger(ctx)
+	log.
+		WithFields(logrus.Fields{
+			"user_id":   ra.UserID,
+			"tenant_id": ra.TenantID,
+			"role":      ra.Role,
+			"event":     event,
+		}).
+		Debug("publishing event")
+}
+
 func (s EventService) log(ctx context.Context, event string, u *user.User) {
 	log := user.Logger(ctx)
 	log.
 		WithFields(logrus.Fields{
-			"user_id": u.ID,
-			"event":   event,
+			"user_id":   u.ID,
+			"tenant_id": u.TenantID,
+			"event":     event,
 		}).
 		Debug("publishing event")
 }
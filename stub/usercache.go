@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/metrics"
 )
 
 type UserStorageCache struct {
@@ -33,9 +34,61 @@ func (c UserStorageCache) Delete(ctx context.Context, id string) error {
 
 func (c UserStorageCache) Get(ctx context.Context, id string) (*user.User, error) {
 	// TODO: Save user in the cache and also etag (if implemented)
+	// nothing is cached yet, so every lookup is a miss.
+	metrics.CacheRequests.WithLabelValues("miss").Inc()
 	return c.storage.Get(ctx, id)
 }
 
 func (c UserStorageCache) List(ctx context.Context, opts *user.ListOptions) (*user.ListResponse, error) {
 	return c.storage.List(ctx, opts)
 }
+
+func (c UserStorageCache) CreateAudited(ctx context.Context, u *user.User, e *user.AuditEntry) error {
+	as, ok := c.storage.(user.AuditedStorage)
+	if !ok {
+		return user.ErrAuditNotSupported
+	}
+	// TODO: invalidate the cache
+	return as.CreateAudited(ctx, u, e)
+}
+
+func (c UserStorageCache) UpdateAudited(ctx context.Context, u *user.User, e *user.AuditEntry) error {
+	as, ok := c.storage.(user.AuditedStorage)
+	if !ok {
+		return user.ErrAuditNotSupported
+	}
+	// TODO: invalidate the cache
+	return as.UpdateAudited(ctx, u, e)
+}
+
+func (c UserStorageCache) DeleteAudited(ctx context.Context, id string, e *user.AuditEntry) error {
+	as, ok := c.storage.(user.AuditedStorage)
+	if !ok {
+		return user.ErrAuditNotSupported
+	}
+	// TODO: invalidate the cache
+	return as.DeleteAudited(ctx, id, e)
+}
+
+func (c UserStorageCache) CreateBatch(ctx context.Context, users []*user.User, entries []*user.AuditEntry, atomic bool) ([]error, error) {
+	bs, ok := c.storage.(user.BatchStorage)
+	if !ok {
+		return nil, user.ErrBatchNotSupported
+	}
+	// TODO: invalidate the cache
+	return bs.CreateBatch(ctx, users, entries, atomic)
+}
+
+func (c UserStorageCache) Export(ctx context.Context, opts *user.ListOptions, fn func(*user.User) error) error {
+	es, ok := c.storage.(user.ExportStorage)
+	if !ok {
+		return user.ErrExportNotSupported
+	}
+	return es.Export(ctx, opts, fn)
+}
+
+// Ping checks the connection with the cache, it's used by the health checks.
+func (c UserStorageCache) Ping(ctx context.Context) error {
+	// TODO: check the cache server
+	return nil
+}
@@ -0,0 +1,38 @@
+package user
+
+import (
+	"context"
+	"regexp"
+)
+
+// DefaultTenant is the tenant of the requests which don't specify one.
+const DefaultTenant = "default"
+
+var ErrInvalidTenant = &Err{Type: InvalidArgument, Code: "invalid_tenant", Message: "Tenant must have up to 64 lowercase letters, digits, - or _"}
+
+var tenantRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+var tenantCtx = contextKey("tenant")
+
+// ValidateTenant returns ErrInvalidTenant when id isn't a valid tenant id.
+func ValidateTenant(id string) error {
+	if !tenantRegexp.MatchString(id) {
+		return ErrInvalidTenant
+	}
+	return nil
+}
+
+// SetTenant stores the tenant of the request, every user read or written
+// with ctx belongs to it.
+func SetTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantCtx, id)
+}
+
+// Tenant returns the tenant of the request, DefaultTenant when it wasn't set.
+func Tenant(ctx context.Context) string {
+	id, _ := ctx.Value(tenantCtx).(string)
+	if id == "" {
+		return DefaultTenant
+	}
+	return id
+}
@@ -0,0 +1,271 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"time"
+
+	"github.com/rs/xid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidCredentials = &Err{Type: Unauthenticated, Code: "invalid_credentials", Message: "Invalid e-mail or password"}
+	ErrInvalidToken       = &Err{Type: Unauthenticated, Code: "invalid_token", Message: "Invalid or expired token"}
+	ErrTokenNotFound      = &Err{Type: NotFound, Code: "token_not_found", Message: "token not found"}
+)
+
+// dummyHash is compared when the user doesn't exist, so it takes the same
+// time as a wrong password and users can't be enumerated.
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), 14)
+	return hash
+})
+
+//go:generate mockgen -package mock -mock_names TokenService=TokenService -destination mock/tokensvc.go github.com/guilherme-santos/user TokenService
+
+// TokenService is an interface which implements the issuance of tokens, so
+// users can authenticate in other services without calling this one.
+type TokenService interface {
+	// IssueToken verifies the password of the user with email and returns a
+	// new access and refresh token.
+	IssueToken(_ context.Context, email, password string) (*Token, error)
+	// RefreshToken exchanges a refresh token, which can be used only once, by
+	// a new pair of tokens.
+	RefreshToken(_ context.Context, refreshToken string) (*Token, error)
+	// VerifyAccessToken returns the claims of a valid access token of an
+	// active user.
+	VerifyAccessToken(_ context.Context, accessToken string) (*TokenClaims, error)
+}
+
+//go:generate mockgen -package mock -mock_names CredentialStorage=CredentialStorage -destination mock/credentialstorage.go github.com/guilherme-santos/user CredentialStorage
+
+// CredentialStorage is implemented by storages which are able to return the
+// password hash of the users.
+type CredentialStorage interface {
+	// GetCredentials returns the id and the bcrypt hash of the password of
+	// the user with email in the tenant of ctx, ErrNotFound is returned if it
+	// doesn't exist.
+	GetCredentials(_ context.Context, email string) (id, hash string, _ error)
+}
+
+//go:generate mockgen -package mock -mock_names RefreshTokenStorage=RefreshTokenStorage -destination mock/refreshtokenstorage.go github.com/guilherme-santos/user RefreshTokenStorage
+
+// RefreshTokenStorage is an interface which implements the storage of refresh
+// tokens, only the hash of the token is stored.
+type RefreshTokenStorage interface {
+	Create(_ context.Context, t *RefreshToken, hash string) error
+	// Consume marks the token with hash as used and returns it as it was
+	// before, ErrTokenNotFound is returned if it doesn't exist.
+	Consume(_ context.Context, hash string) (*RefreshToken, error)
+	// RevokeFamily revokes all tokens issued from the same login.
+	RevokeFamily(_ context.Context, familyID string) error
+	// RevokeUser revokes all tokens of the user with userID.
+	RevokeUser(_ context.Context, userID string) error
+}
+
+//go:generate mockgen -package mock -mock_names TokenSigner=TokenSigner -destination mock/tokensigner.go github.com/guilherme-santos/user TokenSigner
+
+// TokenSigner signs and verifies access tokens.
+type TokenSigner interface {
+	Sign(*TokenClaims) (string, error)
+	// Verify returns the claims of token if its signature is valid and it's
+	// not expired.
+	Verify(token string) (*TokenClaims, error)
+}
+
+// Token is the pair of tokens returned after a successful authentication.
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// TokenClaims are the claims of the access tokens.
+type TokenClaims struct {
+	ID        string
+	Issuer    string
+	Subject   string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	// TenantID is the tenant of the user, as users of different tenants may
+	// have the same e-mail.
+	TenantID string
+}
+
+// RefreshToken is a long lived token used to get new access tokens. Each one
+// can be used only once, using it twice revokes all tokens of its family as
+// it may have been stolen.
+type RefreshToken struct {
+	ID        string
+	FamilyID  string
+	UserID    string
+	TenantID  string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
+
+// TokenOptions contains the options used when issuing tokens.
+type TokenOptions struct {
+	Issuer     string
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+func NewTokenOptions() *TokenOptions {
+	return &TokenOptions{
+		Issuer:     "usersvc",
+		AccessTTL:  15 * time.Minute,
+		RefreshTTL: 30 * 24 * time.Hour,
+	}
+}
+
+type TokenServiceImpl struct {
+	users       Storage
+	credentials CredentialStorage
+	storage     RefreshTokenStorage
+	signer      TokenSigner
+	opts        *TokenOptions
+}
+
+// Make sure TokenServiceImpl implements TokenService
+var _ TokenService = &TokenServiceImpl{}
+
+func NewTokenService(users Storage, credentials CredentialStorage, storage RefreshTokenStorage, signer TokenSigner, opts *TokenOptions) *TokenServiceImpl {
+	if opts == nil {
+		opts = NewTokenOptions()
+	}
+	return &TokenServiceImpl{
+		users:       users,
+		credentials: credentials,
+		storage:     storage,
+		signer:      signer,
+		opts:        opts,
+	}
+}
+
+func (s TokenServiceImpl) IssueToken(ctx context.Context, email, password string) (*Token, error) {
+	if email == "" {
+		return nil, NewMissingFieldError("email")
+	}
+	if password == "" {
+		return nil, NewMissingFieldError("password")
+	}
+
+	id, hash, err := s.credentials.GetCredentials(ctx, email)
+	if err == ErrNotFound {
+		bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return s.issue(ctx, Tenant(ctx), id, xid.New().String())
+}
+
+func (s TokenServiceImpl) RefreshToken(ctx context.Context, refreshToken string) (*Token, error) {
+	if refreshToken == "" {
+		return nil, NewMissingFieldError("refresh_token")
+	}
+
+	t, err := s.storage.Consume(ctx, HashSecret(refreshToken))
+	if err == ErrTokenNotFound {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if t.UsedAt != nil {
+		// the token was used before, someone else may have it
+		err = s.storage.RevokeFamily(ctx, t.FamilyID)
+		if err != nil {
+			return nil, err
+		}
+		Logger(ctx).
+			WithField("user_id", t.UserID).
+			Warn("refresh token reused, all tokens of its family were revoked")
+		return nil, ErrInvalidToken
+	}
+	if t.RevokedAt != nil || !time.Now().Before(t.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+	// tokens of deleted users may not have been revoked yet
+	err = s.active(ctx, t.TenantID, t.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, t.TenantID, t.UserID, t.FamilyID)
+}
+
+// VerifyAccessToken also rejects tokens of deleted users, which are valid
+// until they expire, so they can't be used to read or update their record.
+func (s TokenServiceImpl) VerifyAccessToken(ctx context.Context, accessToken string) (*TokenClaims, error) {
+	claims, err := s.signer.Verify(accessToken)
+	if err != nil || claims.Issuer != s.opts.Issuer {
+		return nil, ErrInvalidToken
+	}
+	err = s.active(ctx, claims.TenantID, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// active returns ErrInvalidToken when the user with id of tenant doesn't
+// exist or was deleted.
+func (s TokenServiceImpl) active(ctx context.Context, tenant, id string) error {
+	u, err := s.users.Get(SetTenant(ctx, tenant), id)
+	if err == ErrNotFound || (err == nil && u.RemovedAt != nil) {
+		return ErrInvalidToken
+	}
+	return err
+}
+
+// issue creates a new access token and a new refresh token of family.
+func (s TokenServiceImpl) issue(ctx context.Context, tenant, userID, family string) (*Token, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	access, err := s.signer.Sign(&TokenClaims{
+		ID:        xid.New().String(),
+		Issuer:    s.opts.Issuer,
+		Subject:   userID,
+		TenantID:  tenant,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(s.opts.AccessTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 32)
+	_, err = rand.Read(b)
+	if err != nil {
+		return nil, err
+	}
+	refresh := base64.RawURLEncoding.EncodeToString(b)
+	err = s.storage.Create(ctx, &RefreshToken{
+		FamilyID:  family,
+		UserID:    userID,
+		TenantID:  tenant,
+		ExpiresAt: now.Add(s.opts.RefreshTTL),
+		CreatedAt: now,
+	}, HashSecret(refresh))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Token{
+		AccessToken:  access,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.opts.AccessTTL.Seconds()),
+		RefreshToken: refresh,
+	}, nil
+}
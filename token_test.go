@@ -0,0 +1,231 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestTokenServiceIssueToken(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+
+	tests := []struct {
+		name     string
+		password string
+		found    bool
+		err      error
+	}{
+		{
+			name:     "valid",
+			password: "secret",
+			found:    true,
+		},
+		{
+			name:     "wrong password",
+			password: "wrong",
+			found:    true,
+			err:      user.ErrInvalidCredentials,
+		},
+		{
+			name:     "unknown user",
+			password: "secret",
+			err:      user.ErrInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := user.SetTenant(context.Background(), "brand-1")
+
+			credentials := mock.NewCredentialStorage(ctrl)
+			if tt.found {
+				credentials.EXPECT().GetCredentials(ctx, "john@doe.com").Return("user-1", string(hash), nil)
+			} else {
+				credentials.EXPECT().GetCredentials(ctx, "john@doe.com").Return("", "", user.ErrNotFound)
+			}
+
+			storage := mock.NewRefreshTokenStorage(ctrl)
+			signer := mock.NewTokenSigner(ctrl)
+			var refreshHash string
+			if tt.err == nil {
+				signer.EXPECT().
+					Sign(gomock.Any()).
+					DoAndReturn(func(c *user.TokenClaims) (string, error) {
+						assert.Equal(t, "user-1", c.Subject)
+						assert.Equal(t, "usersvc", c.Issuer)
+						assert.Equal(t, "brand-1", c.TenantID)
+						assert.Equal(t, 15*time.Minute, c.ExpiresAt.Sub(c.IssuedAt))
+						return "access", nil
+					})
+				storage.EXPECT().
+					Create(ctx, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, rt *user.RefreshToken, h string) error {
+						assert.Equal(t, "user-1", rt.UserID)
+						assert.Equal(t, "brand-1", rt.TenantID)
+						assert.NotEmpty(t, rt.FamilyID)
+						refreshHash = h
+						return nil
+					})
+			}
+
+			svc := user.NewTokenService(nil, credentials, storage, signer, nil)
+			tok, err := svc.IssueToken(ctx, "john@doe.com", tt.password)
+			assert.Equal(t, tt.err, err)
+			if tt.err == nil {
+				assert.Equal(t, "access", tok.AccessToken)
+				assert.Equal(t, "Bearer", tok.TokenType)
+				assert.Equal(t, int64(900), tok.ExpiresIn)
+				// Only the hash of the refresh token is stored
+				assert.Equal(t, user.HashSecret(tok.RefreshToken), refreshHash)
+			}
+		})
+	}
+}
+
+func TestTokenServiceRefreshToken(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		token  *user.RefreshToken
+		user   *user.User
+		err    error
+		revoke bool
+	}{
+		{
+			name:  "valid",
+			token: &user.RefreshToken{FamilyID: "family-1", UserID: "user-1", TenantID: "brand-1", ExpiresAt: future},
+			user:  &user.User{ID: "user-1"},
+		},
+		{
+			name:  "deleted user",
+			token: &user.RefreshToken{FamilyID: "family-1", UserID: "user-1", TenantID: "brand-1", ExpiresAt: future},
+			user:  &user.User{ID: "user-1", RemovedAt: &past},
+			err:   user.ErrInvalidToken,
+		},
+		{
+			name:  "unknown user",
+			token: &user.RefreshToken{FamilyID: "family-1", UserID: "user-1", TenantID: "brand-1", ExpiresAt: future},
+			err:   user.ErrInvalidToken,
+		},
+		{
+			name: "not found",
+			err:  user.ErrInvalidToken,
+		},
+		{
+			name:  "expired",
+			token: &user.RefreshToken{FamilyID: "family-1", UserID: "user-1", ExpiresAt: past},
+			err:   user.ErrInvalidToken,
+		},
+		{
+			name:  "revoked",
+			token: &user.RefreshToken{FamilyID: "family-1", UserID: "user-1", ExpiresAt: future, RevokedAt: &past},
+			err:   user.ErrInvalidToken,
+		},
+		{
+			name:   "reused",
+			token:  &user.RefreshToken{FamilyID: "family-1", UserID: "user-1", ExpiresAt: future, UsedAt: &past},
+			err:    user.ErrInvalidToken,
+			revoke: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+
+			storage := mock.NewRefreshTokenStorage(ctrl)
+			if tt.token != nil {
+				storage.EXPECT().Consume(ctx, user.HashSecret("refresh")).Return(tt.token, nil)
+			} else {
+				storage.EXPECT().Consume(ctx, user.HashSecret("refresh")).Return(nil, user.ErrTokenNotFound)
+			}
+			if tt.revoke {
+				storage.EXPECT().RevokeFamily(ctx, "family-1").Return(nil)
+			}
+
+			// the user is looked up in the tenant of the token
+			users := mock.NewUserStorage(ctrl)
+			if tt.token != nil && tt.token.TenantID != "" {
+				getCtx := user.SetTenant(ctx, "brand-1")
+				if tt.user != nil {
+					users.EXPECT().Get(getCtx, "user-1").Return(tt.user, nil)
+				} else {
+					users.EXPECT().Get(getCtx, "user-1").Return(nil, user.ErrNotFound)
+				}
+			}
+
+			signer := mock.NewTokenSigner(ctrl)
+			if tt.err == nil {
+				signer.EXPECT().Sign(gomock.Any()).Return("access", nil)
+				storage.EXPECT().
+					Create(ctx, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, rt *user.RefreshToken, _ string) error {
+						// the new token belongs to the same family
+						assert.Equal(t, "family-1", rt.FamilyID)
+						return nil
+					})
+			}
+
+			svc := user.NewTokenService(users, nil, storage, signer, nil)
+			_, err := svc.RefreshToken(ctx, "refresh")
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestTokenServiceVerifyAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	removedAt := time.Now()
+
+	signer := mock.NewTokenSigner(ctrl)
+	signer.EXPECT().Verify("valid").Return(&user.TokenClaims{Issuer: "usersvc", Subject: "user-1", TenantID: "brand-1"}, nil)
+	signer.EXPECT().Verify("removed").Return(&user.TokenClaims{Issuer: "usersvc", Subject: "user-2", TenantID: "brand-1"}, nil)
+	signer.EXPECT().Verify("unknown").Return(&user.TokenClaims{Issuer: "usersvc", Subject: "user-3", TenantID: "brand-1"}, nil)
+	signer.EXPECT().Verify("other-issuer").Return(&user.TokenClaims{Issuer: "other", Subject: "user-1"}, nil)
+	signer.EXPECT().Verify("invalid").Return(nil, errors.New("token is expired"))
+
+	// the user is looked up in the tenant of the token
+	getCtx := user.SetTenant(ctx, "brand-1")
+	users := mock.NewUserStorage(ctrl)
+	users.EXPECT().Get(getCtx, "user-1").Return(&user.User{ID: "user-1"}, nil)
+	users.EXPECT().Get(getCtx, "user-2").Return(&user.User{ID: "user-2", RemovedAt: &removedAt}, nil)
+	users.EXPECT().Get(getCtx, "user-3").Return(nil, user.ErrNotFound)
+
+	svc := user.NewTokenService(users, nil, nil, signer, nil)
+
+	claims, err := svc.VerifyAccessToken(ctx, "valid")
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+
+	_, err = svc.VerifyAccessToken(ctx, "removed")
+	assert.Equal(t, user.ErrInvalidToken, err)
+
+	_, err = svc.VerifyAccessToken(ctx, "unknown")
+	assert.Equal(t, user.ErrInvalidToken, err)
+
+	_, err = svc.VerifyAccessToken(ctx, "other-issuer")
+	assert.Equal(t, user.ErrInvalidToken, err)
+
+	_, err = svc.VerifyAccessToken(ctx, "invalid")
+	assert.Equal(t, user.ErrInvalidToken, err)
+}
@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/guilherme-santos/user"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// EventService creates a span for every event published by the decorated service.
+type EventService struct {
+	eventsvc user.EventService
+}
+
+// Make sure EventService implements user.EventService
+var _ user.EventService = &EventService{}
+
+func NewEventService(eventsvc user.EventService) *EventService {
+	return &EventService{
+		eventsvc: eventsvc,
+	}
+}
+
+func (s EventService) UserCreated(ctx context.Context, u *user.User) (err error) {
+	ctx, span := start(ctx, "EventService.UserCreated", eventAttrs("user.created", u)...)
+	defer end(span, &err)
+	return s.eventsvc.UserCreated(ctx, u)
+}
+
+func (s EventService) UserUpdated(ctx context.Context, u *user.User) (err error) {
+	ctx, span := start(ctx, "EventService.UserUpdated", eventAttrs("user.updated", u)...)
+	defer end(span, &err)
+	return s.eventsvc.UserUpdated(ctx, u)
+}
+
+func (s EventService) UserDeleted(ctx context.Context, u *user.User) (err error) {
+	ctx, span := start(ctx, "EventService.UserDeleted", eventAttrs("user.deleted", u)...)
+	defer end(span, &err)
+	return s.eventsvc.UserDeleted(ctx, u)
+}
+
+func (s EventService) RoleGranted(ctx context.Context, ra *user.RoleAssignment) (err error) {
+	ctx, span := start(ctx, "EventService.RoleGranted", roleEventAttrs("role.granted", ra)...)
+	defer end(span, &err)
+	return s.eventsvc.RoleGranted(ctx, ra)
+}
+
+func (s EventService) RoleRevoked(ctx context.Context, ra *user.RoleAssignment) (err error) {
+	ctx, span := start(ctx, "EventService.RoleRevoked", roleEventAttrs("role.revoked", ra)...)
+	defer end(span, &err)
+	return s.eventsvc.RoleRevoked(ctx, ra)
+}
+
+func roleEventAttrs(event string, ra *user.RoleAssignment) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("event", event),
+		attribute.String("user.id", ra.UserID),
+		attribute.String("tenant.id", ra.TenantID),
+		attribute.String("role", ra.Role),
+	}
+}
+
+func eventAttrs(event string, u *user.User) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("event", event),
+		attribute.String("user.id", u.ID),
+		attribute.String("tenant.id", u.TenantID),
+	}
+}
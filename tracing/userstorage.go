@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/guilherme-santos/user"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// UserStorage creates a span for every method of the decorated storage.
+type UserStorage struct {
+	storage user.Storage
+	system  attribute.KeyValue
+}
+
+// Make sure UserStorage implements all storages
+var (
+	_ user.Storage        = &UserStorage{}
+	_ user.AuditedStorage = &UserStorage{}
+	_ user.BatchStorage   = &UserStorage{}
+	_ user.ExportStorage  = &UserStorage{}
+)
+
+// NewUserStorage decorates storage, system is the database used (e.g. mysql)
+// and it's added to every span as db.system.
+func NewUserStorage(storage user.Storage, system string) *UserStorage {
+	return &UserStorage{
+		storage: storage,
+		system:  attribute.String("db.system", system),
+	}
+}
+
+func (s UserStorage) Create(ctx context.Context, u *user.User) (err error) {
+	ctx, span := start(ctx, "UserStorage.Create", s.system)
+	defer end(span, &err)
+	return s.storage.Create(ctx, u)
+}
+
+func (s UserStorage) Update(ctx context.Context, u *user.User) (err error) {
+	ctx, span := start(ctx, "UserStorage.Update", s.system, attribute.String("user.id", u.ID))
+	defer end(span, &err)
+	return s.storage.Update(ctx, u)
+}
+
+func (s UserStorage) Delete(ctx context.Context, id string) (err error) {
+	ctx, span := start(ctx, "UserStorage.Delete", s.system, attribute.String("user.id", id))
+	defer end(span, &err)
+	return s.storage.Delete(ctx, id)
+}
+
+func (s UserStorage) Get(ctx context.Context, id string) (_ *user.User, err error) {
+	ctx, span := start(ctx, "UserStorage.Get", s.system, attribute.String("user.id", id))
+	defer end(span, &err)
+	return s.storage.Get(ctx, id)
+}
+
+func (s UserStorage) List(ctx context.Context, opts *user.ListOptions) (_ *user.ListResponse, err error) {
+	ctx, span := start(ctx, "UserStorage.List", s.system)
+	defer end(span, &err)
+	return s.storage.List(ctx, opts)
+}
+
+func (s UserStorage) CreateAudited(ctx context.Context, u *user.User, e *user.AuditEntry) (err error) {
+	ctx, span := start(ctx, "UserStorage.CreateAudited", s.system)
+	defer end(span, &err)
+	as, ok := s.storage.(user.AuditedStorage)
+	if !ok {
+		return user.ErrAuditNotSupported
+	}
+	return as.CreateAudited(ctx, u, e)
+}
+
+func (s UserStorage) UpdateAudited(ctx context.Context, u *user.User, e *user.AuditEntry) (err error) {
+	ctx, span := start(ctx, "UserStorage.UpdateAudited", s.system, attribute.String("user.id", u.ID))
+	defer end(span, &err)
+	as, ok := s.storage.(user.AuditedStorage)
+	if !ok {
+		return user.ErrAuditNotSupported
+	}
+	return as.UpdateAudited(ctx, u, e)
+}
+
+func (s UserStorage) DeleteAudited(ctx context.Context, id string, e *user.AuditEntry) (err error) {
+	ctx, span := start(ctx, "UserStorage.DeleteAudited", s.system, attribute.String("user.id", id))
+	defer end(span, &err)
+	as, ok := s.storage.(user.AuditedStorage)
+	if !ok {
+		return user.ErrAuditNotSupported
+	}
+	return as.DeleteAudited(ctx, id, e)
+}
+
+func (s UserStorage) CreateBatch(ctx context.Context, users []*user.User, entries []*user.AuditEntry, atomic bool) (_ []error, err error) {
+	ctx, span := start(ctx, "UserStorage.CreateBatch", s.system, attribute.Int("batch.size", len(users)))
+	defer end(span, &err)
+	bs, ok := s.storage.(user.BatchStorage)
+	if !ok {
+		return nil, user.ErrBatchNotSupported
+	}
+	return bs.CreateBatch(ctx, users, entries, atomic)
+}
+
+func (s UserStorage) Export(ctx context.Context, opts *user.ListOptions, fn func(*user.User) error) (err error) {
+	ctx, span := start(ctx, "UserStorage.Export", s.system)
+	defer end(span, &err)
+	es, ok := s.storage.(user.ExportStorage)
+	if !ok {
+		return user.ErrExportNotSupported
+	}
+	return es.Export(ctx, opts, fn)
+}
@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/guilherme-santos/user"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var errNotSupported = &user.Err{Type: user.Unknown, Code: "not_supported", Message: "operation not supported by the service"}
+
+// UserService creates a span for every method of the decorated service.
+type UserService struct {
+	svc user.Service
+}
+
+// Make sure UserService implements all services
+var (
+	_ user.Service       = &UserService{}
+	_ user.ImportService = &UserService{}
+	_ user.ExportService = &UserService{}
+	_ user.AuditService  = &UserService{}
+)
+
+func NewUserService(svc user.Service) *UserService {
+	return &UserService{
+		svc: svc,
+	}
+}
+
+func (s UserService) Create(ctx context.Context, u *user.User) (err error) {
+	ctx, span := start(ctx, "UserService.Create")
+	defer end(span, &err)
+	return s.svc.Create(ctx, u)
+}
+
+func (s UserService) Update(ctx context.Context, u *user.User) (err error) {
+	ctx, span := start(ctx, "UserService.Update", attribute.String("user.id", u.ID))
+	defer end(span, &err)
+	return s.svc.Update(ctx, u)
+}
+
+func (s UserService) Delete(ctx context.Context, id string) (err error) {
+	ctx, span := start(ctx, "UserService.Delete", attribute.String("user.id", id))
+	defer end(span, &err)
+	return s.svc.Delete(ctx, id)
+}
+
+func (s UserService) Get(ctx context.Context, id string) (_ *user.User, err error) {
+	ctx, span := start(ctx, "UserService.Get", attribute.String("user.id", id))
+	defer end(span, &err)
+	return s.svc.Get(ctx, id)
+}
+
+func (s UserService) List(ctx context.Context, opts *user.ListOptions) (_ *user.ListResponse, err error) {
+	ctx, span := start(ctx, "UserService.List")
+	defer end(span, &err)
+	return s.svc.List(ctx, opts)
+}
+
+func (s UserService) Import(ctx context.Context, r user.ImportReader, opts *user.ImportOptions, fn func(*user.ImportResult)) (_ *user.ImportSummary, err error) {
+	ctx, span := start(ctx, "UserService.Import")
+	defer end(span, &err)
+	is, ok := s.svc.(user.ImportService)
+	if !ok {
+		return nil, errNotSupported
+	}
+	return is.Import(ctx, r, opts, fn)
+}
+
+func (s UserService) Export(ctx context.Context, opts *user.ListOptions, fn func(*user.User) error) (err error) {
+	ctx, span := start(ctx, "UserService.Export")
+	defer end(span, &err)
+	es, ok := s.svc.(user.ExportService)
+	if !ok {
+		return errNotSupported
+	}
+	return es.Export(ctx, opts, fn)
+}
+
+func (s UserService) AuditLog(ctx context.Context, userID string, opts *user.AuditListOptions) (_ *user.AuditListResponse, err error) {
+	ctx, span := start(ctx, "UserService.AuditLog", attribute.String("user.id", userID))
+	defer end(span, &err)
+	as, ok := s.svc.(user.AuditService)
+	if !ok {
+		return nil, errNotSupported
+	}
+	return as.AuditLog(ctx, userID, opts)
+}
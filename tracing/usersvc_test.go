@@ -0,0 +1,60 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guilherme-santos/user"
+	"github.com/guilherme-santos/user/mock"
+	"github.com/guilherme-santos/user/tracing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestUserServiceSpans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	ctx := context.Background()
+	u := &user.User{ID: "uuid"}
+
+	storage := mock.NewUserStorage(ctrl)
+	storage.EXPECT().Get(gomock.Any(), "uuid").Return(u, nil)
+	storage.EXPECT().Get(gomock.Any(), "uuid-2").Return(nil, user.ErrNotFound)
+
+	svc := mock.NewUserService(ctrl)
+	tstorage := tracing.NewUserStorage(storage, "mysql")
+	svc.EXPECT().Get(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id string) (*user.User, error) {
+			return tstorage.Get(ctx, id)
+		}).
+		Times(2)
+
+	tsvc := tracing.NewUserService(svc)
+	_, err := tsvc.Get(ctx, "uuid")
+	assert.NoError(t, err)
+	_, err = tsvc.Get(ctx, "uuid-2")
+	assert.Equal(t, user.ErrNotFound, err)
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 4) {
+		return
+	}
+	// storage spans end first and are children of the service ones
+	assert.Equal(t, "UserStorage.Get", spans[0].Name())
+	assert.Equal(t, "UserService.Get", spans[1].Name())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, spans[1].SpanContext().TraceID(), spans[0].SpanContext().TraceID())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+	assert.Equal(t, codes.Error, spans[3].Status().Code)
+}
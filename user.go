@@ -2,12 +2,17 @@ package user
 
 import (
 	"context"
+	"slices"
 	"strings"
 	"time"
 )
 
 var ErrNotFound = &Err{Type: NotFound, Code: "not_found", Message: "user not found"}
 
+var ErrInvalidCursor = &Err{Type: InvalidArgument, Code: "invalid_cursor", Message: "Cursor can only be used sorting by id"}
+
+var ErrInvalidSort = &Err{Type: InvalidArgument, Code: "invalid_sort", Message: "Users can only be sorted by id, first_name, last_name, nickname, email, country, created_at or updated_at"}
+
 //go:generate mockgen -package mock -mock_names Service=UserService -destination mock/usersvc.go github.com/guilherme-santos/user Service
 
 // Service is an interface which implements the basic functions of the service.
@@ -38,6 +43,8 @@ type EventService interface {
 	UserCreated(context.Context, *User) error
 	UserUpdated(context.Context, *User) error
 	UserDeleted(context.Context, *User) error
+	RoleGranted(context.Context, *RoleAssignment) error
+	RoleRevoked(context.Context, *RoleAssignment) error
 }
 
 // User is the representation of a user in the context of faceit
@@ -48,12 +55,18 @@ type User struct {
 	Nickname  string `json:"nickname"`
 	// Password keeps the plain password when creating or updating a user.
 	// Important: It will never be returned to the clients.
-	Password  string     `json:"password,omitempty"`
-	Email     string     `json:"email"`
-	Country   string     `json:"country"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt *time.Time `json:"updated_at"`
-	RemovedAt *time.Time `json:"removed_at,omitempty"`
+	Password string `json:"password,omitempty"`
+	// PasswordHash keeps an already hashed (bcrypt) password, it's only used
+	// when importing users and takes precedence over Password.
+	PasswordHash string     `json:"-"`
+	Email        string     `json:"email"`
+	Country      string     `json:"country"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+	RemovedAt    *time.Time `json:"removed_at,omitempty"`
+	// TenantID is the brand the user belongs to, it's always taken from the
+	// context of the request.
+	TenantID string `json:"tenant_id"`
 }
 
 func (u User) Validate() error {
@@ -64,7 +77,7 @@ func (u User) Validate() error {
 		return NewMissingFieldError("last_name")
 	}
 	// No id, users is been creating, so password is required.
-	if u.Password == "" && u.ID == "" {
+	if u.Password == "" && u.PasswordHash == "" && u.ID == "" {
 		return NewMissingFieldError("password")
 	}
 	// Only check length if password is provided (creating or updating)
@@ -110,7 +123,9 @@ type ListOptions struct {
 	Sort    string
 	PerPage int64
 	Page    int
-	Cursor  string
+	// Cursor is the next_cursor of the previous page, it's the id of its last
+	// user so it can only be used sorting by id.
+	Cursor string
 }
 
 func NewListOptions() *ListOptions {
@@ -119,6 +134,47 @@ func NewListOptions() *ListOptions {
 	}
 }
 
+// SortFields contains all fields users can be sorted by.
+var SortFields = []string{
+	"id",
+	"first_name",
+	"last_name",
+	"nickname",
+	"email",
+	"country",
+	"created_at",
+	"updated_at",
+}
+
+// Validate returns ErrInvalidSort when Sort isn't one of SortFields, and
+// ErrInvalidCursor when Cursor is used without sorting by id.
+func (o ListOptions) Validate() error {
+	field, _ := o.SortField()
+	if !slices.Contains(SortFields, field) {
+		return ErrInvalidSort
+	}
+	if o.Cursor != "" && !o.SortedByID() {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+// SortField returns the field users are sorted by and whether the order is
+// descending, Sort is prefixed by - in such case.
+func (o ListOptions) SortField() (field string, desc bool) {
+	if o.Sort == "" {
+		return "id", false
+	}
+	field, desc = strings.CutPrefix(o.Sort, "-")
+	return field, desc
+}
+
+// SortedByID returns whether users are sorted by id, which is the default.
+// Ids have a timestamp, so it's the order of creation.
+func (o ListOptions) SortedByID() bool {
+	return o.Sort == "" || o.Sort == "id"
+}
+
 // ListResponse contains the list of users returned by List method.
 type ListResponse struct {
 	Total      int64   `json:"total"`
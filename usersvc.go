@@ -1,58 +1,100 @@
 package user
 
-import "context"
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
 
 type ServiceImpl struct {
 	storage  Storage
+	tokens   RefreshTokenStorage
 	eventsvc EventService
+	audit    AuditStorage
 }
 
 // Make sure ServiceImpl implements Service
 var _ Service = &ServiceImpl{}
 
-func NewService(storage Storage, eventsvc EventService) *ServiceImpl {
+func NewService(storage Storage, tokens RefreshTokenStorage, eventsvc EventService, audit AuditStorage) *ServiceImpl {
 	return &ServiceImpl{
 		storage:  storage,
+		tokens:   tokens,
 		eventsvc: eventsvc,
+		audit:    audit,
 	}
 }
 
-// Create creates a user and publish a user.created event to our message broker.
+// Create creates a user, records it in the audit log and publish a user.created
+// event to our message broker.
 func (s ServiceImpl) Create(ctx context.Context, u *User) error {
 	err := u.Validate()
 	if err != nil {
 		return err
 	}
 
-	err = s.storage.Create(ctx, u)
+	as, err := s.audited()
+	if err != nil {
+		return err
+	}
+	err = as.CreateAudited(ctx, u, NewAuditEntry(ctx, AuditCreated, nil, u))
 	if err != nil {
 		return err
 	}
+	ctx = withUserID(ctx, u.ID)
 	return s.eventsvc.UserCreated(ctx, u)
 }
 
-// Update updates a user and publish a user.updated event to our message broker.
+// Update updates a user, records the changes in the audit log and publish a
+// user.updated event to our message broker. Changing the password revokes the
+// refresh tokens of the user.
 func (s ServiceImpl) Update(ctx context.Context, u *User) error {
+	ctx = withUserID(ctx, u.ID)
 	err := u.Validate()
 	if err != nil {
 		return err
 	}
 
-	err = s.storage.Update(ctx, u)
+	as, err := s.audited()
+	if err != nil {
+		return err
+	}
+	before, err := s.storage.Get(ctx, u.ID)
 	if err != nil {
 		return err
 	}
+
+	err = as.UpdateAudited(ctx, u, NewAuditEntry(ctx, AuditUpdated, before, u))
+	if err != nil {
+		return err
+	}
+	if u.Password != "" {
+		err = s.tokens.RevokeUser(ctx, u.ID)
+		if err != nil {
+			return err
+		}
+	}
 	return s.eventsvc.UserUpdated(ctx, u)
 }
 
-// Delete deletes a user and publish a user.deleted event to our message broker.
+// Delete deletes a user, records it in the audit log, revokes its refresh
+// tokens and publish a user.deleted event to our message broker.
 func (s ServiceImpl) Delete(ctx context.Context, id string) error {
+	ctx = withUserID(ctx, id)
+	as, err := s.audited()
+	if err != nil {
+		return err
+	}
 	u, err := s.Get(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	err = s.storage.Delete(ctx, u.ID)
+	err = as.DeleteAudited(ctx, u.ID, NewAuditEntry(ctx, AuditDeleted, u, nil))
+	if err != nil {
+		return err
+	}
+	err = s.tokens.RevokeUser(ctx, u.ID)
 	if err != nil {
 		return err
 	}
@@ -61,6 +103,7 @@ func (s ServiceImpl) Delete(ctx context.Context, id string) error {
 
 // Get retrieves a user by its id.
 func (s ServiceImpl) Get(ctx context.Context, id string) (*User, error) {
+	ctx = withUserID(ctx, id)
 	return s.storage.Get(ctx, id)
 }
 
@@ -69,5 +112,25 @@ func (s ServiceImpl) List(ctx context.Context, opts *ListOptions) (*ListResponse
 	if opts == nil {
 		opts = NewListOptions()
 	}
+	err := opts.Validate()
+	if err != nil {
+		return nil, err
+	}
 	return s.storage.List(ctx, opts)
 }
+
+// audited returns the storage used by mutations, as every one of them is
+// recorded in the audit log within the same transaction.
+func (s ServiceImpl) audited() (AuditedStorage, error) {
+	as, ok := s.storage.(AuditedStorage)
+	if !ok {
+		return nil, ErrAuditNotSupported
+	}
+	return as, nil
+}
+
+// withUserID adds the user_id field to the logger of ctx, so the logs of
+// storage and events can be correlated to the user.
+func withUserID(ctx context.Context, id string) context.Context {
+	return WithLogFields(ctx, logrus.Fields{"user_id": id})
+}
@@ -11,42 +11,92 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// auditedStorage combines both mocks as the service expects a single storage.
+type auditedStorage struct {
+	*mock.UserStorage
+	*mock.AuditedStorage
+}
+
 func TestUserServiceCreate(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	ctx := context.Background()
+	ctx := user.SetActor(context.Background(), "admin")
 	u := newUser()
 
-	// Creates the user in the storage
-	storage := mock.NewUserStorage(ctrl)
-	storage.EXPECT().Create(gomock.Any(), u).Return(nil)
+	// Creates the user and records it in the audit log in the storage
+	storage := mock.NewAuditedStorage(ctrl)
+	storage.EXPECT().
+		CreateAudited(gomock.Any(), u, gomock.Any()).
+		Do(func(_ context.Context, _ *user.User, e *user.AuditEntry) {
+			assert.Equal(t, user.AuditCreated, e.Action)
+			assert.Equal(t, "admin", e.Actor)
+		})
 
 	// Publish a user.created event
 	eventsvc := mock.NewEventService(ctrl)
 	eventsvc.EXPECT().UserCreated(gomock.Any(), u).Return(nil)
 
-	svc := user.NewService(storage, eventsvc)
+	audit := mock.NewAuditStorage(ctrl)
+
+	svc := user.NewService(auditedStorage{AuditedStorage: storage}, nil, eventsvc, audit)
 	err := svc.Create(ctx, u)
 	assert.NoError(t, err)
 }
 
+func TestUserServiceCreateAuditNotSupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Nothing is created without its audit entry
+	storage := mock.NewUserStorage(ctrl)
+	eventsvc := mock.NewEventService(ctrl)
+	audit := mock.NewAuditStorage(ctrl)
+
+	svc := user.NewService(storage, nil, eventsvc, audit)
+	err := svc.Create(context.Background(), newUser())
+	assert.Equal(t, user.ErrAuditNotSupported, err)
+}
+
 func TestUserServiceUpdate(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	ctx := context.Background()
 	u := newUser()
+	u.ID = "uuid"
+	before := newUser()
+	before.ID = "uuid"
+	before.Email = "old@gmail.com"
 
-	// Updates the user in the storage
+	// Get the user from the storage
 	storage := mock.NewUserStorage(ctrl)
-	storage.EXPECT().Update(gomock.Any(), u).Return(nil)
+	storage.EXPECT().Get(gomock.Any(), u.ID).Return(before, nil)
+
+	// Updates it and records the changes in the audit log
+	audited := mock.NewAuditedStorage(ctrl)
+	audited.EXPECT().
+		UpdateAudited(gomock.Any(), u, gomock.Any()).
+		Do(func(_ context.Context, _ *user.User, e *user.AuditEntry) {
+			assert.Equal(t, user.AuditUpdated, e.Action)
+			assert.Equal(t, user.AnonymousActor, e.Actor)
+			assert.Equal(t, map[string]user.AuditChange{
+				"email":    {Before: "old@gmail.com", After: "xguiga@gmail.com"},
+				"password": {Before: user.Redacted, After: user.Redacted},
+			}, e.Changes)
+		})
+
+	// The password changed, refresh tokens are revoked
+	tokens := mock.NewRefreshTokenStorage(ctrl)
+	tokens.EXPECT().RevokeUser(gomock.Any(), u.ID).Return(nil)
 
 	// Publish a user.updated event
 	eventsvc := mock.NewEventService(ctrl)
 	eventsvc.EXPECT().UserUpdated(gomock.Any(), u).Return(nil)
 
-	svc := user.NewService(storage, eventsvc)
+	audit := mock.NewAuditStorage(ctrl)
+
+	svc := user.NewService(auditedStorage{storage, audited}, tokens, eventsvc, audit)
 	err := svc.Update(ctx, u)
 	assert.NoError(t, err)
 }
@@ -59,16 +109,30 @@ func TestUserServiceDelete(t *testing.T) {
 	u := newUser()
 	u.ID = "uuid"
 
-	// Get and deletes the user in the storage
+	// Get the user from the storage
 	storage := mock.NewUserStorage(ctrl)
 	storage.EXPECT().Get(gomock.Any(), u.ID).Return(u, nil)
-	storage.EXPECT().Delete(gomock.Any(), u.ID).Return(nil)
+
+	// Deletes it and records it in the audit log
+	audited := mock.NewAuditedStorage(ctrl)
+	audited.EXPECT().
+		DeleteAudited(gomock.Any(), u.ID, gomock.Any()).
+		Do(func(_ context.Context, _ string, e *user.AuditEntry) {
+			assert.Equal(t, user.AuditDeleted, e.Action)
+			assert.Equal(t, u.ID, e.UserID)
+		})
+
+	// Revokes its refresh tokens
+	tokens := mock.NewRefreshTokenStorage(ctrl)
+	tokens.EXPECT().RevokeUser(gomock.Any(), u.ID).Return(nil)
 
 	// Publish a user.deleted event
 	eventsvc := mock.NewEventService(ctrl)
 	eventsvc.EXPECT().UserDeleted(gomock.Any(), u).Return(nil)
 
-	svc := user.NewService(storage, eventsvc)
+	audit := mock.NewAuditStorage(ctrl)
+
+	svc := user.NewService(auditedStorage{storage, audited}, tokens, eventsvc, audit)
 	err := svc.Delete(ctx, u.ID)
 	assert.NoError(t, err)
 }
@@ -86,8 +150,9 @@ func TestUserServiceGet(t *testing.T) {
 	storage.EXPECT().Get(gomock.Any(), u.ID).Return(u, nil)
 
 	eventsvc := mock.NewEventService(ctrl)
+	audit := mock.NewAuditStorage(ctrl)
 
-	svc := user.NewService(storage, eventsvc)
+	svc := user.NewService(storage, nil, eventsvc, audit)
 	uu, err := svc.Get(ctx, u.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, u, uu)
@@ -116,8 +181,9 @@ func TestUserServiceList(t *testing.T) {
 		}, nil)
 
 	eventsvc := mock.NewEventService(ctrl)
+	audit := mock.NewAuditStorage(ctrl)
 
-	svc := user.NewService(storage, eventsvc)
+	svc := user.NewService(storage, nil, eventsvc, audit)
 	list, err := svc.List(ctx, nil)
 	assert.NoError(t, err)
 	if assert.Len(t, list.Users, 3) {
@@ -127,6 +193,43 @@ func TestUserServiceList(t *testing.T) {
 	}
 }
 
+func TestUserServiceListCursorSort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// cursors are ids, so the storage isn't called sorting by other fields
+	storage := mock.NewUserStorage(ctrl)
+	eventsvc := mock.NewEventService(ctrl)
+	audit := mock.NewAuditStorage(ctrl)
+
+	svc := user.NewService(storage, nil, eventsvc, audit)
+	_, err := svc.List(context.Background(), &user.ListOptions{
+		Sort:    "-first_name",
+		PerPage: 10,
+		Cursor:  "c74tbdnblarkcprj54f0",
+	})
+	assert.Equal(t, user.ErrInvalidCursor, err)
+}
+
+func TestUserServiceListInvalidSort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// only known fields are sent to the storage
+	storage := mock.NewUserStorage(ctrl)
+	eventsvc := mock.NewEventService(ctrl)
+	audit := mock.NewAuditStorage(ctrl)
+
+	svc := user.NewService(storage, nil, eventsvc, audit)
+	for _, sort := range []string{"password", "-password", "id; DROP TABLE user", "--id"} {
+		_, err := svc.List(context.Background(), &user.ListOptions{
+			Sort:    sort,
+			PerPage: 10,
+		})
+		assert.Equal(t, user.ErrInvalidSort, err, sort)
+	}
+}
+
 func newUser() *user.User {
 	return &user.User{
 		FirstName: "Guilherme",